@@ -11,10 +11,19 @@ import (
 
 	// 我们要用到 ShowVocabularyPractice
 	"FiftySound/modules/vocabulary"
+
+	// 学习统计面板
+	"FiftySound/modules/dashboard"
+
+	// 错题本
+	"FiftySound/modules/notebook"
+
+	// 答题记录
+	"FiftySound/modules/history"
 )
 
 func main() {
-	myApp := app.New()
+	myApp := app.NewWithID("com.fiftysound")
 	myWin := myApp.NewWindow("日语学习 - 主菜单")
 
 	// 五十音按钮
@@ -30,11 +39,26 @@ func main() {
 		vocabulary.ShowVocabularyMainPage(myApp, myWin)
 	})
 
+	// 学习统计按钮
+	btnDashboard := widget.NewButton("学习统计", func() {
+		dashboard.ShowDashboard(myApp, myWin)
+	})
+
+	// 错题本按钮
+	btnNotebook := widget.NewButton("错题本", func() {
+		notebook.ShowNotebook(myApp, myWin)
+	})
+
 	myWin.SetContent(container.NewVBox(
 		widget.NewLabel("请选择要进入的功能："),
 		btnFiftySounds,
 		btnVocabulary,
+		btnDashboard,
+		btnNotebook,
 	))
 	myWin.Resize(fyne.NewSize(400, 300))
 	myWin.ShowAndRun()
+
+	// 退出前保存还没写入的答题记录
+	history.Open(myApp).Flush()
 }
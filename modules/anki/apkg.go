@@ -0,0 +1,229 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ==================================================
+// Anki 卡组包 (.apkg)：ZIP 中的 collection.anki21 / collection.anki2 是 SQLite 数据库，
+// 笔记的各字段以 0x1f 分隔保存在 notes.flds 中
+// ==================================================
+
+// ErrNewFormat 卡组只有新版 Anki 的压缩格式 (collection.anki21b)
+var ErrNewFormat = errors.New("这是新版 Anki 的卡组格式，请在 Anki 导出时勾选\"兼容旧版本 Anki\"后重新导出")
+
+const fieldSeparator = "\x1f"
+
+// Model 笔记类型
+type Model struct {
+	ID     int64
+	Name   string
+	Fields []string // 按顺序排列的字段名
+}
+
+// Note 一条笔记
+type Note struct {
+	ModelID int64
+	Deck    []string // 所在的牌组，如 "日语::第1课" 为 ["日语", "第1课"]
+	Fields  []string
+	Tags    []string
+}
+
+// Package 从 .apkg 中读出的笔记类型和笔记
+type Package struct {
+	Models []*Model // 按名字排序
+	Notes  []Note
+}
+
+// Model 按 ID 查找笔记类型
+func (p *Package) Model(id int64) *Model {
+	for _, m := range p.Models {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// ReadPackage 解析 .apkg 文件
+func ReadPackage(data []byte) (*Package, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("ZIP解压失败: %w", err)
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	// 新版 Anki 导出的包里 collection.anki2 只是一条"请升级"的占位笔记
+	f := files["collection.anki21"]
+	if f == nil {
+		if files["collection.anki21b"] != nil {
+			return nil, ErrNewFormat
+		}
+		f = files["collection.anki2"]
+	}
+	if f == nil {
+		return nil, errors.New("不是 Anki 卡组包：缺少 collection.anki2")
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	raw, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openDatabase(raw)
+	if err != nil {
+		return nil, err
+	}
+	return readCollection(db)
+}
+
+func readCollection(db *database) (*Package, error) {
+	models, decks, err := readModelsAndDecks(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// 笔记所在的牌组取它第一张卡片的牌组
+	cards, err := db.rows("cards")
+	if err != nil {
+		return nil, err
+	}
+	type cardDeck struct {
+		ord  int64
+		deck int64
+	}
+	noteDeck := make(map[int64]cardDeck)
+	for _, c := range cards {
+		nid, ord, did := asInt(c["nid"]), asInt(c["ord"]), asInt(c["did"])
+		if cd, ok := noteDeck[nid]; !ok || ord < cd.ord {
+			noteDeck[nid] = cardDeck{ord: ord, deck: did}
+		}
+	}
+
+	notes, err := db.rows("notes")
+	if err != nil {
+		return nil, err
+	}
+	pkg := &Package{}
+	used := make(map[int64]bool)
+	for _, n := range notes {
+		flds, _ := n["flds"].(string)
+		tags, _ := n["tags"].(string)
+		note := Note{
+			ModelID: asInt(n["mid"]),
+			Fields:  strings.Split(flds, fieldSeparator),
+			Tags:    strings.Fields(tags),
+		}
+		if cd, ok := noteDeck[asInt(n["id"])]; ok {
+			note.Deck = decks[cd.deck]
+		}
+		used[note.ModelID] = true
+		pkg.Notes = append(pkg.Notes, note)
+	}
+
+	for id, m := range models {
+		if used[id] {
+			pkg.Models = append(pkg.Models, m)
+		}
+	}
+	sort.Slice(pkg.Models, func(i, j int) bool { return pkg.Models[i].Name < pkg.Models[j].Name })
+	return pkg, nil
+}
+
+// readModelsAndDecks 读取 col 表中以 JSON 保存的笔记类型和牌组。
+// 兼容旧版的导出 (schema 11) 都是这种格式
+func readModelsAndDecks(db *database) (map[int64]*Model, map[int64][]string, error) {
+	models := make(map[int64]*Model)
+	decks := make(map[int64][]string)
+
+	cols, err := db.rows("col")
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(cols) == 0 {
+		return nil, nil, errors.New("col 表为空")
+	}
+
+	var jsonModels map[string]struct {
+		Name string `json:"name"`
+		Flds []struct {
+			Name string `json:"name"`
+			Ord  int    `json:"ord"`
+		} `json:"flds"`
+	}
+	if s, _ := cols[0]["models"].(string); s != "" {
+		if err := json.Unmarshal([]byte(s), &jsonModels); err != nil {
+			return nil, nil, fmt.Errorf("笔记类型解析失败: %w", err)
+		}
+	}
+	for idStr, jm := range jsonModels {
+		id, _ := strconv.ParseInt(idStr, 10, 64)
+		flds := jm.Flds
+		sort.Slice(flds, func(i, j int) bool { return flds[i].Ord < flds[j].Ord })
+		m := &Model{ID: id, Name: jm.Name}
+		for _, f := range flds {
+			m.Fields = append(m.Fields, f.Name)
+		}
+		models[id] = m
+	}
+
+	var jsonDecks map[string]struct {
+		Name string `json:"name"`
+	}
+	if s, _ := cols[0]["decks"].(string); s != "" {
+		if err := json.Unmarshal([]byte(s), &jsonDecks); err != nil {
+			return nil, nil, fmt.Errorf("牌组解析失败: %w", err)
+		}
+	}
+	for idStr, jd := range jsonDecks {
+		id, _ := strconv.ParseInt(idStr, 10, 64)
+		decks[id] = strings.Split(jd.Name, "::")
+	}
+	return models, decks, nil
+}
+
+func asInt(v any) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case float64:
+		return int64(x)
+	case string:
+		n, _ := strconv.ParseInt(x, 10, 64)
+		return n
+	}
+	return 0
+}
+
+var (
+	breakTag = regexp.MustCompile(`(?i)<br\s*/?>|</div>|</p>`)
+	htmlTag  = regexp.MustCompile(`<[^>]*>`)
+	soundTag = regexp.MustCompile(`\[sound:[^\]]*\]`)
+)
+
+// PlainText 把 Anki 字段中的 HTML 转成纯文本：换行标签变成换行，去掉其它标签、音频引用和实体
+func PlainText(field string) string {
+	s := breakTag.ReplaceAllString(field, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = soundTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = strings.ReplaceAll(s, "\u00a0", " ")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,165 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testdata/sample.apkg 由 SQLite 生成（页大小 512，使 B 树有多层、长字段进入溢出页）：
+// 150 条"日语单词"笔记，前 100 条在 日语::第1课，其余在 日语::第2课；
+// 每条笔记还有一张 ord 为 1、在 Default 牌组的卡片
+func TestReadPackage(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.apkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := ReadPackage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pkg.Models) != 1 {
+		t.Fatalf("got %d models, want only the used one", len(pkg.Models))
+	}
+	m := pkg.Models[0]
+	if m.Name != "日语单词" || !reflect.DeepEqual(m.Fields, []string{"假名", "汉字", "中文"}) {
+		t.Errorf("model = %+v", m)
+	}
+	if pkg.Model(m.ID) != m || pkg.Model(1) != nil {
+		t.Error("Model lookup by ID failed")
+	}
+
+	if len(pkg.Notes) != 150 {
+		t.Fatalf("got %d notes, want 150", len(pkg.Notes))
+	}
+	first := pkg.Notes[0]
+	if !reflect.DeepEqual(first.Deck, []string{"日语", "第1课"}) {
+		t.Errorf("deck = %v", first.Deck)
+	}
+	if !reflect.DeepEqual(first.Tags, []string{"n5", "tag0"}) {
+		t.Errorf("tags = %v", first.Tags)
+	}
+	if first.Fields[0] != "ねこ" || PlainText(first.Fields[2]) != "猫\n& cat" {
+		t.Errorf("fields = %q", first.Fields)
+	}
+	if long := pkg.Notes[1].Fields[2]; long != strings.Repeat("長", 600) {
+		t.Errorf("overflow field has %d runes", len([]rune(long)))
+	}
+	if last := pkg.Notes[149]; last.Fields[1] != "単語149" || !reflect.DeepEqual(last.Deck, []string{"日语", "第2课"}) {
+		t.Errorf("last note = %+v", last)
+	}
+}
+
+func TestReadPackageErrors(t *testing.T) {
+	zipWith := func(name string) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, _ := zw.Create(name)
+		w.Write([]byte("not a database"))
+		zw.Close()
+		return buf.Bytes()
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"not zip", []byte("hello"), nil},
+		{"missing collection", zipWith("media"), nil},
+		{"new format", zipWith("collection.anki21b"), ErrNewFormat},
+		{"not sqlite", zipWith("collection.anki2"), nil},
+	}
+	for _, tt := range tests {
+		_, err := ReadPackage(tt.data)
+		if err == nil {
+			t.Errorf("%s: no error", tt.name)
+			continue
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"猫", "猫"},
+		{"<b>猫</b>", "猫"},
+		{"ねこ<br>猫<BR/>cat", "ねこ\n猫\ncat"},
+		{"<div>一</div><div>二</div>", "一\n二"},
+		{"猫[sound:neko.mp3]", "猫"},
+		{"A &amp; B&nbsp;C", "A & B C"},
+		{"  <p>猫</p>  ", "猫"},
+	}
+	for _, tt := range tests {
+		if got := PlainText(tt.in); got != tt.want {
+			t.Errorf("PlainText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		sql      string
+		cols     []string
+		rowidCol int
+	}{
+		{"CREATE TABLE t (id integer primary key, name text)", []string{"id", "name"}, 0},
+		{"CREATE TABLE t (a text, id INTEGER PRIMARY KEY)", []string{"a", "id"}, 1},
+		{"CREATE TABLE t (a text, b integer not null)", []string{"a", "b"}, -1},
+		{"CREATE TABLE t (\"a\" text, `b` numeric(10, 2), PRIMARY KEY (a))", []string{"a", "b"}, -1},
+		{"CREATE TABLE t (id integer, CONSTRAINT pk PRIMARY KEY (id))", []string{"id"}, -1},
+		{"CREATE TABLE t", nil, -1},
+	}
+	for _, tt := range tests {
+		cols, rowidCol := parseColumns(tt.sql)
+		if !reflect.DeepEqual(cols, tt.cols) || rowidCol != tt.rowidCol {
+			t.Errorf("parseColumns(%q) = %q, %d, want %q, %d", tt.sql, cols, rowidCol, tt.cols, tt.rowidCol)
+		}
+	}
+}
+
+func TestReadVarint(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want uint64
+		n    int
+	}{
+		{[]byte{0x00}, 0, 1},
+		{[]byte{0x7f}, 127, 1},
+		{[]byte{0x81, 0x00}, 128, 2},
+		{[]byte{0x82, 0x2c}, 300, 2},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 1<<64 - 1, 9},
+	}
+	for _, tt := range tests {
+		v, n := readVarint(tt.in)
+		if v != tt.want || n != tt.n {
+			t.Errorf("readVarint(% x) = %d, %d, want %d, %d", tt.in, v, n, tt.want, tt.n)
+		}
+	}
+}
+
+func TestReadInt(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want int64
+	}{
+		{[]byte{0x01}, 1},
+		{[]byte{0xff}, -1},
+		{[]byte{0x01, 0x00}, 256},
+		{[]byte{0xff, 0x00}, -256},
+		{[]byte{0x00, 0x00, 0x01, 0x00, 0x00, 0x00}, 1 << 24},
+	}
+	for _, tt := range tests {
+		if got := readInt(tt.in); got != tt.want {
+			t.Errorf("readInt(% x) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"FiftySound/modules/srs"
+)
+
+// ==================================================
+// 生成 Anki 卡组包：schema 11 的 collection.anki2，
+// 新旧版本的 Anki 都可以导入；可以带上复习进度
+// ==================================================
+
+// 写入数据库的表结构与 Anki schema 11 相同
+const (
+	colSQL    = "CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null, models text not null, decks text not null, dconf text not null, tags text not null)"
+	notesSQL  = "CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null, flags integer not null, data text not null)"
+	cardsSQL  = "CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null, mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null, ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null)"
+	revlogSQL = "CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null)"
+	gravesSQL = "CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null)"
+)
+
+const defaultDeckID = 1
+
+// CardState 卡片的复习进度，导出时换算成 Anki 的复习卡
+type CardState struct {
+	Due      time.Time
+	Interval float64 // 天
+	Ease     float64 // 难度系数，如 2.5
+	Reps     int
+	Lapses   int
+}
+
+// ExportNote 要导出的一条笔记，每条笔记生成一张卡片
+type ExportNote struct {
+	Key    string   // 用来生成稳定的 guid，再次导入时 Anki 会更新而不是重复添加
+	Deck   []string // 牌组路径，为空时放在 Deck.Name 下
+	Fields []string
+	Tags   []string
+	State  *CardState // 为 nil 时作为新卡片
+}
+
+// Deck 要导出的卡组
+type Deck struct {
+	Name   string   // 顶层牌组名
+	Model  string   // 笔记类型名
+	Fields []string // 字段名
+	Front  string   // 卡片正面模板，如 "{{假名}}"
+	Back   string   // 卡片背面模板
+	Notes  []ExportNote
+}
+
+// WritePackage 生成 .apkg 文件
+func WritePackage(d Deck) ([]byte, error) {
+	now := time.Now()
+	modSecs := now.Unix()
+	modelID := idFromString("model:" + d.Model)
+
+	// 复习卡的到期日以 collection 创建日为基准，取最早到期日之前的零点
+	crt := startOfDay(now)
+	for _, n := range d.Notes {
+		if n.State != nil && n.State.Interval >= 1 && n.State.Due.Before(crt) {
+			crt = startOfDay(n.State.Due)
+		}
+	}
+
+	// 牌组：顶层牌组 + 笔记用到的子牌组（及其上级）
+	deckIDs := map[string]int64{"Default": defaultDeckID}
+	addDeck := func(path []string) int64 {
+		var id int64
+		for i := range path {
+			name := strings.Join(path[:i+1], "::")
+			if _, ok := deckIDs[name]; !ok {
+				deckIDs[name] = idFromString("deck:" + name)
+			}
+			id = deckIDs[name]
+		}
+		return id
+	}
+	addDeck([]string{d.Name})
+
+	var notes, cards [][]any
+	for i, n := range d.Notes {
+		nid := modSecs*1000 + int64(i)
+		did := addDeck(append([]string{d.Name}, n.Deck...))
+		sfld := ""
+		if len(n.Fields) > 0 {
+			sfld = n.Fields[0]
+		}
+		tags := ""
+		if len(n.Tags) > 0 {
+			tags = " " + strings.Join(n.Tags, " ") + " "
+		}
+		notes = append(notes, []any{
+			nid, guid(n.Key), modelID, modSecs, -1, tags,
+			strings.Join(n.Fields, fieldSeparator), sfld, checksum(sfld), 0, "",
+		})
+
+		typ, queue, due, ivl, factor, reps, lapses := 0, 0, int64(i+1), 0, 0, 0, 0
+		if s := n.State; s != nil {
+			reps, lapses = s.Reps, s.Lapses
+			factor = int(math.Round(s.Ease * 1000))
+			if s.Interval >= 1 {
+				typ, queue = 2, 2
+				ivl = int(math.Round(s.Interval))
+				due = int64(startOfDay(s.Due).Sub(crt).Hours() / 24)
+			}
+		}
+		cards = append(cards, []any{
+			nid, nid, did, 0, modSecs, -1, typ, queue, due, ivl, factor, reps, lapses, 0, 0, 0, 0, "",
+		})
+	}
+
+	models, err := json.Marshal(map[string]any{
+		strconv.FormatInt(modelID, 10): modelJSON(modelID, d, modSecs),
+	})
+	if err != nil {
+		return nil, err
+	}
+	decks := make(map[string]any)
+	for name, id := range deckIDs {
+		decks[strconv.FormatInt(id, 10)] = deckJSON(id, name, modSecs)
+	}
+	decksData, err := json.Marshal(decks)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := json.Marshal(map[string]any{
+		"nextPos": len(d.Notes) + 1, "estTimes": true, "activeDecks": []int{defaultDeckID},
+		"sortType": "noteFld", "timeLim": 0, "sortBackwards": false, "addToCur": true,
+		"curDeck": defaultDeckID, "newBury": true, "newSpread": 0, "dueCounts": true,
+		"curModel": strconv.FormatInt(modelID, 10), "collapseTime": 1200,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	db := buildDatabase([]dbTable{
+		{name: "col", sql: colSQL, rows: [][]any{{
+			int64(1), crt.Unix(), modSecs * 1000, modSecs * 1000, 11, 0, 0, 0,
+			string(conf), string(models), string(decksData), defaultDeckConf, "{}",
+		}}},
+		{name: "notes", sql: notesSQL, rows: notes},
+		{name: "cards", sql: cardsSQL, rows: cards},
+		{name: "revlog", sql: revlogSQL},
+		{name: "graves", sql: gravesSQL},
+	})
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("collection.anki2")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(db); err != nil {
+		return nil, err
+	}
+	if w, err = zw.Create("media"); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write([]byte("{}")); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func modelJSON(id int64, d Deck, mod int64) map[string]any {
+	var flds []map[string]any
+	for i, f := range d.Fields {
+		flds = append(flds, map[string]any{
+			"name": f, "ord": i, "sticky": false, "rtl": false,
+			"font": "Arial", "size": 20, "media": []any{},
+		})
+	}
+	return map[string]any{
+		"id": id, "name": d.Model, "type": 0, "mod": mod, "usn": -1, "sortf": 0,
+		"did": defaultDeckID, "flds": flds,
+		"tmpls": []map[string]any{{
+			"name": "Card 1", "ord": 0, "qfmt": d.Front, "afmt": d.Back,
+			"did": nil, "bqfmt": "", "bafmt": "",
+		}},
+		"css":       ".card { font-family: arial; font-size: 24px; text-align: center; }",
+		"latexPre":  "\\documentclass[12pt]{article}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"latexsvg":  false,
+		"req":       []any{[]any{0, "any", []int{0}}},
+		"tags":      []any{},
+		"vers":      []any{},
+	}
+}
+
+func deckJSON(id int64, name string, mod int64) map[string]any {
+	return map[string]any{
+		"id": id, "name": name, "mod": mod, "usn": -1,
+		"lrnToday": []int{0, 0}, "revToday": []int{0, 0}, "newToday": []int{0, 0}, "timeToday": []int{0, 0},
+		"collapsed": false, "desc": "", "dyn": 0, "conf": 1, "extendNew": 10, "extendRev": 50,
+	}
+}
+
+const defaultDeckConf = `{"1":{"id":1,"name":"Default","mod":0,"usn":0,"maxTaken":60,"autoplay":true,"timer":0,"replayq":true,"dyn":false,` +
+	`"new":{"delays":[1,10],"ints":[1,4,7],"initialFactor":2500,"order":1,"perDay":20,"bury":true},` +
+	`"rev":{"perDay":200,"ease4":1.3,"ivlFct":1,"maxIvl":36500,"bury":true},` +
+	`"lapse":{"delays":[10],"mult":0,"minInt":1,"leechFails":8,"leechAction":0}}}`
+
+// idFromString 由名字生成稳定的 ID（毫秒时间戳量级），同名的牌组/笔记类型每次导出 ID 相同
+func idFromString(s string) int64 {
+	sum := sha1.Sum([]byte(s))
+	return 1_000_000_000_000 + int64(binary.BigEndian.Uint32(sum[:4]))
+}
+
+// guid 由 key 生成稳定的笔记 guid
+func guid(key string) string {
+	sum := sha1.Sum([]byte("note:" + key))
+	return base64.RawStdEncoding.EncodeToString(sum[:8])
+}
+
+// checksum Anki 用排序字段 SHA1 的前 8 位十六进制作为重复检查的校验和
+func checksum(s string) int64 {
+	sum := sha1.Sum([]byte(PlainText(s)))
+	n, _ := strconv.ParseInt(hex.EncodeToString(sum[:4]), 16, 64)
+	return n
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// StateFromSRS 把本程序的复习进度换算成 Anki 的卡片状态，从未复习过时返回 nil
+func StateFromSRS(c srs.Card, reviewed bool) *CardState {
+	if !reviewed {
+		return nil
+	}
+	return &CardState{
+		Due:      c.Due,
+		Interval: c.Interval,
+		Ease:     c.Ease,
+		Reps:     c.Repetitions,
+		Lapses:   c.Lapses,
+	}
+}
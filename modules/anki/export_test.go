@@ -0,0 +1,210 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"FiftySound/modules/srs"
+)
+
+func TestVarintRoundTrip(t *testing.T) {
+	for _, v := range []uint64{0, 1, 127, 128, 240, 16383, 16384, 1 << 32, 0x00ffffffffffffff, 0x0100000000000000, math.MaxUint64} {
+		b := appendVarint(nil, v)
+		got, n := readVarint(append(b, 0, 0, 0, 0, 0, 0, 0, 0, 0))
+		if got != v || n != len(b) || varintLen(v) != len(b) {
+			t.Errorf("varint %d: encoded % x, read %d using %d bytes", v, b, got, n)
+		}
+	}
+}
+
+func TestEncodeRecord(t *testing.T) {
+	tests := []struct {
+		in   any
+		want any
+	}{
+		{nil, nil},
+		{0, int64(0)},
+		{1, int64(1)},
+		{int64(-1), int64(-1)},
+		{127, int64(127)},
+		{-129, int64(-129)},
+		{1 << 20, int64(1 << 20)},
+		{-1 << 23, int64(-1 << 23)},
+		{int64(1) << 40, int64(1) << 40},
+		{int64(math.MinInt64), int64(math.MinInt64)},
+		{true, int64(1)},
+		{2.5, 2.5},
+		{"", ""},
+		{"日本語", "日本語"},
+		{[]byte{1, 2, 3}, []byte{1, 2, 3}},
+	}
+	var in, want []any
+	for _, tt := range tests {
+		in = append(in, tt.in)
+		want = append(want, tt.want)
+	}
+	d := &database{encoding: encodingUTF8}
+	got, err := d.decodeRecord(encodeRecord(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeRecord(encodeRecord(%v)) = %v, want %v", in, got, want)
+	}
+}
+
+// 行数和长字段足够多，使 B 树有多层内部页并用到溢出页
+func TestBuildDatabase(t *testing.T) {
+	var rows [][]any
+	for i := 3000; i >= 1; i-- {
+		text := fmt.Sprintf("row %d", i)
+		if i%500 == 0 {
+			text = strings.Repeat("長", 3000+i)
+		}
+		rows = append(rows, []any{int64(i), text, float64(i) / 2})
+	}
+	data := buildDatabase([]dbTable{
+		{name: "items", sql: "CREATE TABLE items (id integer primary key, text text, half real)", rows: rows},
+		{name: "plain", sql: "CREATE TABLE plain (a text)", rows: [][]any{{"x"}, {"y"}}},
+		{name: "empty", sql: "CREATE TABLE empty (a text)"},
+	})
+
+	db, err := openDatabase(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := db.rows("items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(rows) {
+		t.Fatalf("read %d rows, want %d", len(got), len(rows))
+	}
+	for i, r := range got {
+		id := int64(i + 1)
+		want := fmt.Sprintf("row %d", id)
+		if id%500 == 0 {
+			want = strings.Repeat("長", 3000+int(id))
+		}
+		if r["id"] != id || r["text"] != want || r["half"] != float64(id)/2 {
+			t.Fatalf("row %d = id %v, %d bytes of text, half %v", i, r["id"], len(fmt.Sprint(r["text"])), r["half"])
+		}
+	}
+
+	plain, err := db.rows("plain")
+	if err != nil || len(plain) != 2 || plain[0]["a"] != "x" || plain[1]["a"] != "y" {
+		t.Errorf("plain = %v, %v", plain, err)
+	}
+	if empty, err := db.rows("empty"); err != nil || len(empty) != 0 {
+		t.Errorf("empty = %v, %v", empty, err)
+	}
+}
+
+func TestWritePackage(t *testing.T) {
+	now := time.Now()
+	d := Deck{
+		Name:   "日语",
+		Model:  "FiftySound 单词",
+		Fields: []string{"假名", "汉字", "中文"},
+		Front:  "{{假名}}",
+		Back:   "{{FrontSide}}<hr>{{汉字}}<br>{{中文}}",
+		Notes: []ExportNote{
+			{Key: "ねこ|猫", Deck: []string{"第1课"}, Fields: []string{"ねこ", "猫", "猫"}, Tags: []string{"n5"},
+				State: &CardState{Due: now.Add(72 * time.Hour), Interval: 6, Ease: 2.5, Reps: 2}},
+			{Key: "いぬ|犬", Deck: []string{"第1课"}, Fields: []string{"いぬ", "犬", "狗"}},
+			{Key: "とり|鳥", Fields: []string{"とり", "鳥", "鸟"},
+				State: &CardState{Due: now.Add(time.Minute), Ease: 2.3, Lapses: 1}},
+		},
+	}
+	data, err := WritePackage(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := ReadPackage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkg.Models) != 1 || pkg.Models[0].Name != d.Model || !reflect.DeepEqual(pkg.Models[0].Fields, d.Fields) {
+		t.Fatalf("models = %+v", pkg.Models)
+	}
+	if len(pkg.Notes) != len(d.Notes) {
+		t.Fatalf("got %d notes, want %d", len(pkg.Notes), len(d.Notes))
+	}
+	wantDecks := [][]string{{"日语", "第1课"}, {"日语", "第1课"}, {"日语"}}
+	for i, n := range pkg.Notes {
+		if !reflect.DeepEqual(n.Fields, d.Notes[i].Fields) || !reflect.DeepEqual(n.Deck, wantDecks[i]) ||
+			len(n.Tags) != len(d.Notes[i].Tags) {
+			t.Errorf("note %d = %+v", i, n)
+		}
+	}
+
+	// 复习进度：有间隔的换算成复习卡，没有的仍是新卡片
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := zr.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := io.ReadAll(rc)
+	rc.Close()
+	db, err := openDatabase(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards, err := db.rows("cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		typ, ivl, factor, reps, lapses int64
+	}{
+		{2, 6, 2500, 2, 0},
+		{0, 0, 0, 0, 0},
+		{0, 0, 2300, 0, 1},
+	}
+	for i, tt := range tests {
+		c := cards[i]
+		got := []int64{asInt(c["type"]), asInt(c["ivl"]), asInt(c["factor"]), asInt(c["reps"]), asInt(c["lapses"])}
+		if want := []int64{tt.typ, tt.ivl, tt.factor, tt.reps, tt.lapses}; !reflect.DeepEqual(got, want) {
+			t.Errorf("card %d: type/ivl/factor/reps/lapses = %v, want %v", i, got, want)
+		}
+	}
+	if due := asInt(cards[0]["due"]); due != 3 {
+		t.Errorf("review card due = %d days after creation, want 3", due)
+	}
+}
+
+// 同样的 key 和名字每次导出得到相同的 guid 和 ID，再次导入时 Anki 会更新而不是重复添加
+func TestStableIDs(t *testing.T) {
+	if guid("ねこ|猫") != guid("ねこ|猫") || guid("ねこ|猫") == guid("いぬ|犬") {
+		t.Error("guid is not stable per key")
+	}
+	if idFromString("deck:日语") != idFromString("deck:日语") || idFromString("deck:日语") == idFromString("deck:英语") {
+		t.Error("idFromString is not stable per name")
+	}
+	// 校验和按去掉 HTML 后的排序字段计算
+	if checksum("<b>猫</b>") != checksum("猫") {
+		t.Error("checksum does not strip HTML")
+	}
+}
+
+func TestStateFromSRS(t *testing.T) {
+	c := srs.Card{Ease: 2.4, Interval: 10, Repetitions: 3, Lapses: 1, Due: time.Unix(1700000000, 0)}
+	if StateFromSRS(c, false) != nil {
+		t.Error("unreviewed card should export as new")
+	}
+	want := &CardState{Due: c.Due, Interval: 10, Ease: 2.4, Reps: 3, Lapses: 1}
+	if got := StateFromSRS(c, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("StateFromSRS = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,361 @@
+package anki
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf16"
+)
+
+// ==================================================
+// 只读的 SQLite 解析：Anki 的 collection 就是一个 SQLite 数据库，
+// 这里只实现读取普通表（rowid 表）需要的部分，不依赖 cgo
+// ==================================================
+
+const sqliteMagic = "SQLite format 3\x00"
+
+// 页类型
+const (
+	interiorTablePage = 0x05
+	leafTablePage     = 0x0d
+)
+
+// 文本编码
+const (
+	encodingUTF8    = 1
+	encodingUTF16LE = 2
+	encodingUTF16BE = 3
+)
+
+var errCorrupt = errors.New("数据库文件已损坏")
+
+// database 内存中的 SQLite 数据库
+type database struct {
+	data     []byte
+	pageSize int
+	usable   int // 每页可用字节数（页大小减去保留字节）
+	encoding uint32
+	tables   map[string]*table
+}
+
+// table 一张表的根页和列名
+type table struct {
+	name     string
+	root     int
+	columns  []string
+	rowidCol int // INTEGER PRIMARY KEY 列的下标，该列的值就是 rowid；没有时为 -1
+}
+
+// dbRow 一行数据，值的类型为 int64、float64、string、[]byte 或 nil
+type dbRow map[string]any
+
+func openDatabase(data []byte) (*database, error) {
+	if len(data) < 100 || string(data[:16]) != sqliteMagic {
+		return nil, errors.New("不是 SQLite 数据库")
+	}
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, errCorrupt
+	}
+	d := &database{
+		data:     data,
+		pageSize: pageSize,
+		usable:   pageSize - int(data[20]),
+		encoding: binary.BigEndian.Uint32(data[56:60]),
+		tables:   make(map[string]*table),
+	}
+	if d.encoding == 0 {
+		d.encoding = encodingUTF8
+	}
+
+	// sqlite_master 的根页固定是第 1 页
+	master := &table{
+		name:     "sqlite_master",
+		root:     1,
+		columns:  []string{"type", "name", "tbl_name", "rootpage", "sql"},
+		rowidCol: -1,
+	}
+	rows, err := d.readTable(master)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		if r["type"] != "table" {
+			continue
+		}
+		name, _ := r["name"].(string)
+		root, _ := r["rootpage"].(int64)
+		sql, _ := r["sql"].(string)
+		cols, rowidCol := parseColumns(sql)
+		d.tables[strings.ToLower(name)] = &table{name: name, root: int(root), columns: cols, rowidCol: rowidCol}
+	}
+	return d, nil
+}
+
+// rows 读取整张表
+func (d *database) rows(name string) ([]dbRow, error) {
+	t, ok := d.tables[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("数据库中没有 %s 表", name)
+	}
+	return d.readTable(t)
+}
+
+func (d *database) readTable(t *table) (rows []dbRow, err error) {
+	// 文件损坏时各种下标都可能越界，统一转成错误
+	defer func() {
+		if r := recover(); r != nil {
+			rows, err = nil, errCorrupt
+		}
+	}()
+	visited := make(map[int]bool)
+	err = d.walk(t.root, visited, func(rowid int64, payload []byte) error {
+		values, err := d.decodeRecord(payload)
+		if err != nil {
+			return err
+		}
+		row := make(dbRow, len(t.columns))
+		for i, c := range t.columns {
+			switch {
+			case i == t.rowidCol:
+				row[c] = rowid
+			case i < len(values):
+				row[c] = values[i]
+			default:
+				row[c] = nil // ALTER TABLE 之后新增的列
+			}
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	return rows, err
+}
+
+// page 返回第 n 页（从 1 开始）的内容
+func (d *database) page(n int) ([]byte, error) {
+	start := (n - 1) * d.pageSize
+	if n < 1 || start+d.pageSize > len(d.data) {
+		return nil, errCorrupt
+	}
+	return d.data[start : start+d.pageSize], nil
+}
+
+// walk 按 rowid 顺序遍历表的 B 树，对每一行调用 fn
+func (d *database) walk(n int, visited map[int]bool, fn func(rowid int64, payload []byte) error) error {
+	if visited[n] {
+		return errCorrupt
+	}
+	visited[n] = true
+
+	pg, err := d.page(n)
+	if err != nil {
+		return err
+	}
+	hdr := 0
+	if n == 1 {
+		hdr = 100 // 第 1 页前 100 字节是文件头
+	}
+	kind := pg[hdr]
+	cells := int(binary.BigEndian.Uint16(pg[hdr+3:]))
+
+	switch kind {
+	case leafTablePage:
+		ptrs := hdr + 8
+		for i := 0; i < cells; i++ {
+			off := int(binary.BigEndian.Uint16(pg[ptrs+2*i:]))
+			size, k := readVarint(pg[off:])
+			off += k
+			rowid, k := readVarint(pg[off:])
+			off += k
+			payload, err := d.readPayload(pg, off, int(size))
+			if err != nil {
+				return err
+			}
+			if err := fn(int64(rowid), payload); err != nil {
+				return err
+			}
+		}
+	case interiorTablePage:
+		ptrs := hdr + 12
+		for i := 0; i < cells; i++ {
+			off := int(binary.BigEndian.Uint16(pg[ptrs+2*i:]))
+			child := int(binary.BigEndian.Uint32(pg[off:]))
+			if err := d.walk(child, visited, fn); err != nil {
+				return err
+			}
+		}
+		right := int(binary.BigEndian.Uint32(pg[hdr+8:]))
+		return d.walk(right, visited, fn)
+	default:
+		return fmt.Errorf("%w: 第 %d 页不是表页 (0x%02x)", errCorrupt, n, kind)
+	}
+	return nil
+}
+
+// readPayload 读取叶子页中从 off 开始、总长 size 的记录，超出本页的部分在溢出页链表中
+func (d *database) readPayload(pg []byte, off, size int) ([]byte, error) {
+	u := d.usable
+	maxLocal := u - 35
+	if size <= maxLocal {
+		return pg[off : off+size], nil
+	}
+	minLocal := (u-12)*32/255 - 23
+	local := minLocal + (size-minLocal)%(u-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+
+	payload := make([]byte, 0, size)
+	payload = append(payload, pg[off:off+local]...)
+	next := int(binary.BigEndian.Uint32(pg[off+local:]))
+	for len(payload) < size {
+		if next == 0 {
+			return nil, errCorrupt
+		}
+		ov, err := d.page(next)
+		if err != nil {
+			return nil, err
+		}
+		take := min(u-4, size-len(payload))
+		payload = append(payload, ov[4:4+take]...)
+		next = int(binary.BigEndian.Uint32(ov))
+	}
+	return payload, nil
+}
+
+// decodeRecord 按 SQLite 记录格式解码一行
+func (d *database) decodeRecord(rec []byte) ([]any, error) {
+	hdrSize, k := readVarint(rec)
+	if int(hdrSize) > len(rec) {
+		return nil, errCorrupt
+	}
+	var types []uint64
+	for p := k; p < int(hdrSize); {
+		t, k := readVarint(rec[p:])
+		types = append(types, t)
+		p += k
+	}
+
+	values := make([]any, len(types))
+	p := int(hdrSize)
+	for i, t := range types {
+		switch {
+		case t == 0:
+			values[i] = nil
+		case t >= 1 && t <= 6:
+			n := []int{0, 1, 2, 3, 4, 6, 8}[t]
+			values[i] = readInt(rec[p : p+n])
+			p += n
+		case t == 7:
+			values[i] = math.Float64frombits(binary.BigEndian.Uint64(rec[p:]))
+			p += 8
+		case t == 8:
+			values[i] = int64(0)
+		case t == 9:
+			values[i] = int64(1)
+		case t >= 12 && t%2 == 0:
+			n := int(t-12) / 2
+			values[i] = bytes.Clone(rec[p : p+n])
+			p += n
+		case t >= 13:
+			n := int(t-13) / 2
+			values[i] = d.decodeText(rec[p : p+n])
+			p += n
+		default:
+			return nil, errCorrupt
+		}
+	}
+	return values, nil
+}
+
+func (d *database) decodeText(b []byte) string {
+	if d.encoding == encodingUTF8 {
+		return string(b)
+	}
+	order := binary.ByteOrder(binary.LittleEndian)
+	if d.encoding == encodingUTF16BE {
+		order = binary.BigEndian
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = order.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// readVarint 读取 SQLite 的变长整数，返回值和占用的字节数
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v<<8 | uint64(b[8]), 9
+}
+
+// readInt 读取大端有符号整数
+func readInt(b []byte) int64 {
+	var v int64
+	if b[0]&0x80 != 0 {
+		v = -1
+	}
+	for _, c := range b {
+		v = v<<8 | int64(c)
+	}
+	return v
+}
+
+// parseColumns 从 CREATE TABLE 语句中取出列名，并找出 INTEGER PRIMARY KEY 列
+func parseColumns(sql string) ([]string, int) {
+	start := strings.Index(sql, "(")
+	end := strings.LastIndex(sql, ")")
+	if start < 0 || end <= start {
+		return nil, -1
+	}
+
+	// 按不在括号内的逗号切分
+	var defs []string
+	depth, last := 0, start+1
+	for i := start + 1; i < end; i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				defs = append(defs, sql[last:i])
+				last = i + 1
+			}
+		}
+	}
+	defs = append(defs, sql[last:end])
+
+	var cols []string
+	rowidCol := -1
+	for _, def := range defs {
+		words := strings.Fields(def)
+		if len(words) == 0 {
+			continue
+		}
+		switch strings.ToLower(words[0]) {
+		case "constraint", "primary", "unique", "check", "foreign":
+			continue
+		}
+		name := strings.Trim(words[0], "\"`[]'")
+		upper := strings.ToUpper(def)
+		if len(words) > 1 && strings.EqualFold(words[1], "integer") && strings.Contains(upper, "PRIMARY KEY") {
+			rowidCol = len(cols)
+		}
+		cols = append(cols, name)
+	}
+	return cols, rowidCol
+}
@@ -0,0 +1,323 @@
+package anki
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// ==================================================
+// 生成 SQLite 数据库文件：只支持一次性写入若干普通表（不建索引），
+// 足够生成 Anki 可以导入的 collection
+// ==================================================
+
+const (
+	writePageSize = 4096
+	sqliteVersion = 3040001 // 写在文件头里的 SQLite 版本号
+)
+
+// dbTable 要写入的一张表；INTEGER PRIMARY KEY 列的值作为 rowid，没有这样的列时按顺序编号
+type dbTable struct {
+	name string
+	sql  string
+	rows [][]any // 值的类型为 int64、int、float64、string、[]byte 或 nil
+}
+
+// dbWriter 按顺序分配页
+type dbWriter struct {
+	pages [][]byte // pages[0] 是第 1 页
+}
+
+func (w *dbWriter) newPage() (int, []byte) {
+	pg := make([]byte, writePageSize)
+	w.pages = append(w.pages, pg)
+	return len(w.pages), pg
+}
+
+// buildDatabase 生成包含 tables 的数据库文件
+func buildDatabase(tables []dbTable) []byte {
+	w := &dbWriter{}
+	w.newPage() // 第 1 页留给 sqlite_master
+
+	var master []cellData
+	for i, t := range tables {
+		_, rowidCol := parseColumns(t.sql)
+		var rows []cellData
+		for j, r := range t.rows {
+			rowid := int64(j + 1)
+			values := r
+			if rowidCol >= 0 {
+				rowid = toInt64(r[rowidCol])
+				values = append([]any(nil), r...)
+				values[rowidCol] = nil // rowid 列在记录中存为 NULL
+			}
+			rows = append(rows, cellData{rowid: rowid, payload: encodeRecord(values)})
+		}
+		root := w.writeTree(rows)
+		master = append(master, cellData{
+			rowid:   int64(i + 1),
+			payload: encodeRecord([]any{"table", t.name, t.name, int64(root), t.sql}),
+		})
+	}
+
+	// sqlite_master 只用第 1 页，表不多时足够
+	var masterCells [][]byte
+	for _, c := range master {
+		masterCells = append(masterCells, w.leafCell(c))
+	}
+	writeLeaf(w.pages[0], 100, masterCells)
+
+	hdr := w.pages[0]
+	copy(hdr, sqliteMagic)
+	binary.BigEndian.PutUint16(hdr[16:], writePageSize)
+	hdr[18], hdr[19] = 1, 1 // 读写版本：非 WAL
+	hdr[20] = 0             // 每页保留字节
+	hdr[21], hdr[22], hdr[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(hdr[24:], 1) // 修改计数
+	binary.BigEndian.PutUint32(hdr[28:], uint32(len(w.pages)))
+	binary.BigEndian.PutUint32(hdr[40:], 1) // schema cookie
+	binary.BigEndian.PutUint32(hdr[44:], 4) // schema 格式
+	binary.BigEndian.PutUint32(hdr[56:], encodingUTF8)
+	binary.BigEndian.PutUint32(hdr[92:], 1)
+	binary.BigEndian.PutUint32(hdr[96:], sqliteVersion)
+
+	out := make([]byte, 0, len(w.pages)*writePageSize)
+	for _, pg := range w.pages {
+		out = append(out, pg...)
+	}
+	return out
+}
+
+// cellData 表中的一行
+type cellData struct {
+	rowid   int64
+	payload []byte
+}
+
+// writeTree 把各行写成 B 树，返回根页号
+func (w *dbWriter) writeTree(rows []cellData) int {
+	sort.Slice(rows, func(i, j int) bool { return rows[i].rowid < rows[j].rowid })
+
+	// 先生成所有单元格，溢出页只分配一次
+	cells := make([][]byte, len(rows))
+	for i, r := range rows {
+		cells[i] = w.leafCell(r)
+	}
+
+	type child struct {
+		page   int
+		maxKey int64
+	}
+
+	// 叶子层；空表也要有一个空的叶子页
+	var level []child
+	for start := 0; ; {
+		n, pg := w.newPage()
+		start += writeLeaf(pg, 0, cells[start:])
+		key := int64(0)
+		if start > 0 {
+			key = rows[start-1].rowid
+		}
+		level = append(level, child{page: n, maxKey: key})
+		if start >= len(cells) {
+			break
+		}
+	}
+
+	// 逐层向上生成内部页，直到只剩一个根。
+	// 内部页的单元格是 4 字节子页号 + 该子树最大的 rowid，最后一个子页放在页头的右指针中
+	for len(level) > 1 {
+		var next []child
+		for start := 0; start < len(level); {
+			n, pg := w.newPage()
+			space := writePageSize - 12
+			end := start // level[start:end] 写成单元格，level[end] 作为右指针
+			for end+1 < len(level) {
+				need := 2 + 4 + varintLen(uint64(level[end].maxKey))
+				if need > space {
+					break
+				}
+				space -= need
+				end++
+			}
+			// 避免最后一页只剩一个子页、没有单元格
+			if len(level)-(end+1) == 1 && end > start {
+				end--
+			}
+
+			cellsEnd := writePageSize
+			ptr := 12
+			for i := start; i < end; i++ {
+				cell := binary.BigEndian.AppendUint32(nil, uint32(level[i].page))
+				cell = appendVarint(cell, uint64(level[i].maxKey))
+				cellsEnd -= len(cell)
+				copy(pg[cellsEnd:], cell)
+				binary.BigEndian.PutUint16(pg[ptr:], uint16(cellsEnd))
+				ptr += 2
+			}
+			pg[0] = interiorTablePage
+			binary.BigEndian.PutUint16(pg[3:], uint16(end-start))
+			binary.BigEndian.PutUint16(pg[5:], uint16(cellsEnd))
+			binary.BigEndian.PutUint32(pg[8:], uint32(level[end].page))
+			next = append(next, child{page: n, maxKey: level[end].maxKey})
+			start = end + 1
+		}
+		level = next
+	}
+	return level[0].page
+}
+
+// writeLeaf 在叶子页中从 hdr 处写页头，尽量多地放入 cells，返回放入的个数
+func writeLeaf(pg []byte, hdr int, cells [][]byte) int {
+	cellsEnd := writePageSize
+	ptr := hdr + 8
+	count := 0
+	for _, cell := range cells {
+		if ptr+2 > cellsEnd-len(cell) {
+			break
+		}
+		cellsEnd -= len(cell)
+		copy(pg[cellsEnd:], cell)
+		binary.BigEndian.PutUint16(pg[ptr:], uint16(cellsEnd))
+		ptr += 2
+		count++
+	}
+	pg[hdr] = leafTablePage
+	binary.BigEndian.PutUint16(pg[hdr+3:], uint16(count))
+	binary.BigEndian.PutUint16(pg[hdr+5:], uint16(cellsEnd))
+	return count
+}
+
+// leafCell 生成叶子页单元格，放不下的部分写入溢出页
+func (w *dbWriter) leafCell(c cellData) []byte {
+	size := len(c.payload)
+	cell := appendVarint(nil, uint64(size))
+	cell = appendVarint(cell, uint64(c.rowid))
+
+	u := writePageSize
+	maxLocal := u - 35
+	if size <= maxLocal {
+		return append(cell, c.payload...)
+	}
+	minLocal := (u-12)*32/255 - 23
+	local := minLocal + (size-minLocal)%(u-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+	cell = append(cell, c.payload[:local]...)
+
+	// 溢出页链表：每页前 4 字节是下一页的页号
+	rest := c.payload[local:]
+	first, pg := w.newPage()
+	cell = binary.BigEndian.AppendUint32(cell, uint32(first))
+	for {
+		n := copy(pg[4:], rest)
+		rest = rest[n:]
+		if len(rest) == 0 {
+			break
+		}
+		next, nextPg := w.newPage()
+		binary.BigEndian.PutUint32(pg, uint32(next))
+		pg = nextPg
+	}
+	return cell
+}
+
+// encodeRecord 按 SQLite 记录格式编码一行
+func encodeRecord(values []any) []byte {
+	var types, body []byte
+	for _, v := range values {
+		switch x := v.(type) {
+		case nil:
+			types = appendVarint(types, 0)
+		case string:
+			types = appendVarint(types, uint64(13+2*len(x)))
+			body = append(body, x...)
+		case []byte:
+			types = appendVarint(types, uint64(12+2*len(x)))
+			body = append(body, x...)
+		case float64:
+			types = appendVarint(types, 7)
+			body = binary.BigEndian.AppendUint64(body, math.Float64bits(x))
+		default:
+			n := toInt64(x)
+			t, size := intSerialType(n)
+			types = appendVarint(types, t)
+			for i := size - 1; i >= 0; i-- {
+				body = append(body, byte(n>>(8*i)))
+			}
+		}
+	}
+	// 记录头长度包含它自己
+	hdrLen := len(types) + 1
+	for varintLen(uint64(hdrLen)) != hdrLen-len(types) {
+		hdrLen++
+	}
+	rec := appendVarint(nil, uint64(hdrLen))
+	rec = append(rec, types...)
+	return append(rec, body...)
+}
+
+// intSerialType 选择能放下 n 的最小整数类型，返回类型编号和字节数
+func intSerialType(n int64) (uint64, int) {
+	switch {
+	case n == 0:
+		return 8, 0
+	case n == 1:
+		return 9, 0
+	case n >= math.MinInt8 && n <= math.MaxInt8:
+		return 1, 1
+	case n >= math.MinInt16 && n <= math.MaxInt16:
+		return 2, 2
+	case n >= -1<<23 && n < 1<<23:
+		return 3, 3
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		return 4, 4
+	case n >= -1<<47 && n < 1<<47:
+		return 5, 6
+	}
+	return 6, 8
+}
+
+func toInt64(v any) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case int:
+		return int64(x)
+	case bool:
+		if x {
+			return 1
+		}
+	}
+	return 0
+}
+
+// appendVarint 追加 SQLite 的变长整数
+func appendVarint(b []byte, v uint64) []byte {
+	if v > 0x00ffffffffffffff {
+		// 9 字节：前 8 字节各 7 位，最后一字节 8 位
+		var buf [9]byte
+		buf[8] = byte(v)
+		v >>= 8
+		for i := 7; i >= 0; i-- {
+			buf[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+		return append(b, buf[:]...)
+	}
+	var buf [8]byte
+	i := len(buf) - 1
+	buf[i] = byte(v & 0x7f)
+	v >>= 7
+	for v > 0 {
+		i--
+		buf[i] = byte(v&0x7f) | 0x80
+		v >>= 7
+	}
+	return append(b, buf[i:]...)
+}
+
+func varintLen(v uint64) int {
+	return len(appendVarint(nil, v))
+}
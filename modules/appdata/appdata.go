@@ -0,0 +1,82 @@
+package appdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+)
+
+// ==================================================
+// 应用本地存储：把各模块的数据以 JSON 文件保存在 Fyne 的应用存储目录中
+// 需要 main.go 使用 app.NewWithID 创建应用，否则没有存储目录
+// ==================================================
+
+// ReadFile 读取 name 对应的文件，文件不存在时 ok 为 false
+func ReadFile(a fyne.App, name string) (data []byte, ok bool, err error) {
+	if !exists(a, name) {
+		return nil, false, nil
+	}
+
+	rc, err := a.Storage().Open(name)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rc.Close()
+
+	data, err = io.ReadAll(rc)
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// WriteFile 把 data 写入 name 对应的文件，文件不存在时自动创建
+func WriteFile(a fyne.App, name string, data []byte) error {
+	wc, err := a.Storage().Save(name)
+	if errors.Is(err, storage.ErrNotExists) {
+		wc, err = a.Storage().Create(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := wc.Write(data); err != nil {
+		wc.Close()
+		return err
+	}
+	return wc.Close()
+}
+
+// LoadJSON 读取 name 对应的文件并解析到 v；文件不存在时不修改 v 并返回 nil
+func LoadJSON(a fyne.App, name string, v any) error {
+	data, ok, err := ReadFile(a, name)
+	if err != nil || !ok || len(data) == 0 {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("解析%s失败: %w", name, err)
+	}
+	return nil
+}
+
+// SaveJSON 把 v 序列化后写入 name 对应的文件，文件不存在时自动创建
+func SaveJSON(a fyne.App, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(a, name, data)
+}
+
+func exists(a fyne.App, name string) bool {
+	for _, n := range a.Storage().List() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package appdata
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// SaveAs 弹出保存对话框，把导出的 data 写到用户选择的位置，fileName 为默认文件名
+func SaveAs(win fyne.Window, fileName string, data []byte) {
+	fd := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		if w == nil {
+			return
+		}
+		_, err = w.Write(data)
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		dialog.ShowInformation("导出成功", "已保存到 "+w.URI().Path(), win)
+	}, win)
+	fd.SetFileName(fileName)
+	fd.Show()
+}
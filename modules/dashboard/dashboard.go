@@ -0,0 +1,252 @@
+package dashboard
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/fifty_sounds"
+	"FiftySound/modules/history"
+)
+
+// ==================================================
+// 学习统计面板：根据答题记录画出每日正确率、五十音热力图、易混假名和单元掌握度
+// ==================================================
+
+const (
+	trendDays     = 14 // 趋势图显示最近多少天
+	chartWidth    = 560
+	chartHeight   = 260
+	chartPadding  = 36
+	heatCellSize  = 40
+	barWidth      = 260
+	barHeight     = 18
+	maxConfusions = 15
+)
+
+var (
+	axisColor    = color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+	lineColor    = color.NRGBA{R: 0x20, G: 0x80, B: 0xd0, A: 0xff}
+	noDataColor  = color.NRGBA{R: 0xdd, G: 0xdd, B: 0xdd, A: 0xff}
+	barBackColor = color.NRGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}
+)
+
+// ShowDashboard 打开学习统计窗口
+func ShowDashboard(myApp fyne.App, parent fyne.Window) {
+	win := myApp.NewWindow("学习统计")
+	log := history.Open(myApp)
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("每日正确率", container.NewScroll(trendChart(log))),
+		container.NewTabItem("五十音热力图", container.NewScroll(kanaHeatMap(log))),
+		container.NewTabItem("易混假名", container.NewScroll(confusionList(log))),
+		container.NewTabItem("单元掌握度", container.NewScroll(unitMastery(log))),
+	)
+
+	win.SetContent(container.NewBorder(
+		nil,
+		widget.NewButton("关闭", func() { win.Close() }),
+		nil, nil,
+		tabs,
+	))
+	win.Resize(fyne.NewSize(700, 520))
+	win.Show()
+}
+
+// accuracyColor 正确率 0% 为红色，100% 为绿色
+func accuracyColor(acc history.Accuracy) color.Color {
+	if acc.Total == 0 {
+		return noDataColor
+	}
+	r := acc.Rate() / 100
+	return color.NRGBA{
+		R: uint8(220 * (1 - r)),
+		G: uint8(60 + 140*r),
+		B: 60,
+		A: 0xff,
+	}
+}
+
+// ======================= 每日正确率 =======================
+func trendChart(log *history.Log) fyne.CanvasObject {
+	today := time.Now()
+	start := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -(trendDays - 1))
+	byDay := log.AccuracyByDay(history.Filter{Since: start})
+
+	plotW := float32(chartWidth - 2*chartPadding)
+	plotH := float32(chartHeight - 2*chartPadding)
+	toPos := func(i int, rate float64) fyne.Position {
+		x := chartPadding + plotW*float32(i)/float32(trendDays-1)
+		y := chartPadding + plotH*(1-float32(rate/100))
+		return fyne.NewPos(x, y)
+	}
+
+	var objs []fyne.CanvasObject
+
+	// 坐标轴和 0/50/100% 刻度
+	for _, rate := range []float64{0, 50, 100} {
+		p1 := toPos(0, rate)
+		p2 := toPos(trendDays-1, rate)
+		grid := canvas.NewLine(axisColor)
+		grid.StrokeWidth = 1
+		grid.Position1, grid.Position2 = p1, p2
+		objs = append(objs, grid)
+
+		t := canvas.NewText(fmt.Sprintf("%.0f%%", rate), axisColor)
+		t.TextSize = 11
+		t.Move(fyne.NewPos(2, p1.Y-8))
+		objs = append(objs, t)
+	}
+
+	var prev *fyne.Position
+	for i := 0; i < trendDays; i++ {
+		day := start.AddDate(0, 0, i)
+		if i%2 == 0 || i == trendDays-1 {
+			t := canvas.NewText(day.Format("01-02"), axisColor)
+			t.TextSize = 11
+			p := toPos(i, 0)
+			t.Move(fyne.NewPos(p.X-14, p.Y+6))
+			objs = append(objs, t)
+		}
+
+		acc, ok := byDay[day.Format(history.DayLayout)]
+		if !ok || acc.Total == 0 {
+			continue
+		}
+		p := toPos(i, acc.Rate())
+		if prev != nil {
+			l := canvas.NewLine(lineColor)
+			l.StrokeWidth = 2
+			l.Position1, l.Position2 = *prev, p
+			objs = append(objs, l)
+		}
+		dot := canvas.NewCircle(lineColor)
+		dot.Move(fyne.NewPos(p.X-4, p.Y-4))
+		dot.Resize(fyne.NewSize(8, 8))
+		objs = append(objs, dot)
+
+		label := canvas.NewText(fmt.Sprintf("%d", acc.Total), axisColor)
+		label.TextSize = 10
+		label.Move(fyne.NewPos(p.X-6, p.Y-20))
+		objs = append(objs, label)
+		prev = &p
+	}
+
+	chart := container.NewWithoutLayout(objs...)
+	bg := canvas.NewRectangle(color.Transparent)
+	bg.SetMinSize(fyne.NewSize(chartWidth, chartHeight))
+
+	total := log.Accuracy(history.Filter{Since: start})
+	return container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("最近 %d 天共答题 %d 次，正确率 %.2f%%（折线上的数字为当天答题数）", trendDays, total.Total, total.Rate())),
+		container.NewStack(bg, chart),
+	)
+}
+
+// ======================= 五十音热力图 =======================
+func kanaHeatMap(log *history.Log) fyne.CanvasObject {
+	byItem := log.AccuracyByItem(history.Filter{Module: history.ModuleKana})
+
+	cell := func(kana string) fyne.CanvasObject {
+		acc := byItem[kana]
+		rect := canvas.NewRectangle(accuracyColor(acc))
+		rect.SetMinSize(fyne.NewSize(heatCellSize, heatCellSize))
+		text := canvas.NewText(kana, color.Black)
+		text.Alignment = fyne.TextAlignCenter
+		text.TextSize = 16
+		return container.NewStack(rect, container.NewCenter(text))
+	}
+
+	grid := func(title string, pick func(fifty_sounds.KanaRow) []string) fyne.CanvasObject {
+		box := container.NewVBox(widget.NewLabel(title))
+		for _, row := range fifty_sounds.KanaRows() {
+			items := []fyne.CanvasObject{}
+			for _, k := range pick(row) {
+				items = append(items, cell(k))
+			}
+			if len(items) == 0 {
+				// 只有片假名的行在平假名一侧留空，保持两边对齐
+				gap := canvas.NewRectangle(color.Transparent)
+				gap.SetMinSize(fyne.NewSize(heatCellSize, heatCellSize))
+				items = append(items, gap)
+			}
+			box.Add(container.NewHBox(items...))
+		}
+		return box
+	}
+
+	return container.NewVBox(
+		widget.NewLabel("颜色越绿正确率越高，越红越低，灰色表示还没有答题记录"),
+		container.NewHBox(
+			grid("平假名", func(r fifty_sounds.KanaRow) []string { return r.Hiragana }),
+			layout.NewSpacer(),
+			grid("片假名", func(r fifty_sounds.KanaRow) []string { return r.Katakana }),
+		),
+	)
+}
+
+// ======================= 易混假名 =======================
+func confusionList(log *history.Log) fyne.CanvasObject {
+	pairs := fifty_sounds.ConfusedPairs(log)
+	if len(pairs) == 0 {
+		return widget.NewLabel("还没有混淆记录")
+	}
+	if len(pairs) > maxConfusions {
+		pairs = pairs[:maxConfusions]
+	}
+
+	maxCount := pairs[0].Count
+	box := container.NewVBox(widget.NewLabel("答错时最常混淆的假名"))
+	for _, p := range pairs {
+		box.Add(barRow(fmt.Sprintf("%s ↔ %s", p.A, p.B), float32(p.Count)/float32(maxCount),
+			lineColor, fmt.Sprintf("%d 次", p.Count)))
+	}
+	return box
+}
+
+// ======================= 单元掌握度 =======================
+func unitMastery(log *history.Log) fyne.CanvasObject {
+	byUnit := log.AccuracyByGroup(history.Filter{Module: history.ModuleWord})
+	if len(byUnit) == 0 {
+		return widget.NewLabel("还没有单词练习记录")
+	}
+
+	units := make([]string, 0, len(byUnit))
+	for u := range byUnit {
+		units = append(units, u)
+	}
+	sort.Strings(units)
+
+	box := container.NewVBox(widget.NewLabel("各单元单词练习的正确率"))
+	for _, u := range units {
+		acc := byUnit[u]
+		box.Add(barRow(u, float32(acc.Rate()/100), accuracyColor(acc),
+			fmt.Sprintf("%.0f%% (%d/%d)", acc.Rate(), acc.Correct, acc.Total)))
+	}
+	return box
+}
+
+// barRow 一行横向条形图：名称、按 ratio(0~1) 填充的条、数值
+func barRow(name string, ratio float32, fill color.Color, value string) fyne.CanvasObject {
+	back := canvas.NewRectangle(barBackColor)
+	back.Resize(fyne.NewSize(barWidth, barHeight))
+	bar := canvas.NewRectangle(fill)
+	bar.Resize(fyne.NewSize(barWidth*ratio, barHeight))
+
+	holder := canvas.NewRectangle(color.Transparent)
+	holder.SetMinSize(fyne.NewSize(barWidth, barHeight))
+	bars := container.NewStack(holder, container.NewWithoutLayout(back, bar))
+
+	return container.NewHBox(
+		container.NewGridWrap(fyne.NewSize(220, barHeight+16), widget.NewLabel(name)),
+		container.NewCenter(bars),
+		widget.NewLabel(value),
+	)
+}
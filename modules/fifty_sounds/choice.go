@@ -0,0 +1,199 @@
+package fifty_sounds
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/session"
+)
+
+// ======================= 模式五、六： 四选一 =======================
+// 模式五给出假名，从 4 个罗马音中选择；模式六给出罗马音，从 4 个假名中选择。
+// 干扰项优先取字形相近的假名（见 confusionGroups）和同一行的假名，
+// 不够时再从同一种假名中随机补足。可以按数字键 1-4 选择，回车进入下一题
+
+const choiceCount = 4
+
+// distractors 为 kana 挑选 n 个干扰项：同一种假名、罗马音互不相同
+func distractors(kana string, n int) []string {
+	var near []string
+	for _, g := range confusionGroups {
+		if contains(g, kana) {
+			near = append(near, g...)
+		}
+	}
+	for _, line := range gojuon {
+		if contains(line.hiragana, kana) || contains(line.katakana, kana) {
+			near = append(near, line.hiragana...)
+			near = append(near, line.katakana...)
+		}
+	}
+	rand.Shuffle(len(near), func(i, j int) { near[i], near[j] = near[j], near[i] })
+
+	var others []string
+	for _, line := range gojuon {
+		if !line.extended {
+			others = append(others, line.hiragana...)
+			others = append(others, line.katakana...)
+		}
+	}
+	rand.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+
+	hira := isHiragana(kana)
+	res := []string{}
+	for _, k := range append(near, others...) {
+		if len(res) == n {
+			break
+		}
+		if isHiragana(k) != hira || k == kana || sameSound(k, kana) || contains(res, k) {
+			continue
+		}
+		dup := false
+		for _, r := range res {
+			dup = dup || sameSound(k, r)
+		}
+		if !dup {
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+// romajiLabel 假名的主要罗马音写法，用作选项
+func romajiLabel(kana string) string {
+	return kanaToRomaji[kana][0]
+}
+
+// showChoiceMode 四选一，reverse 为 false 时看假名选罗马音，为 true 时看罗马音选假名
+func showChoiceMode(myApp fyne.App, targets []string, mainWin fyne.Window, stats *Stats, statsLabel *widget.Label, reverse bool, length session.Length) {
+	mode, title := modeFiveName, "模式五"
+	if reverse {
+		mode, title = modeSixName, "模式六"
+	}
+	w := myApp.NewWindow(title)
+	progress := widget.NewLabel("")
+	question := widget.NewLabel("")
+	feedback := widget.NewLabel("")
+	hint := widget.NewLabel("可以按数字键 1-4 选择，按回车进入下一题")
+
+	sess := session.New(length, len(targets))
+	pool := newKanaPool(myApp, mode, targets, sess)
+	var currentKana string
+	var options []string
+	answered := false
+
+	buttons := make([]*widget.Button, choiceCount)
+	choose := func(i int) {
+		if answered || i >= len(options) {
+			return
+		}
+		answered = true
+		for _, b := range buttons {
+			b.Disable()
+		}
+
+		given := options[i]
+		if !reverse {
+			given = romajiLabel(given)
+		}
+		correct := options[i] == currentKana
+		stats.Total++
+		if correct {
+			stats.Correct++
+			feedback.SetText("正确")
+		} else {
+			feedback.SetText(fmt.Sprintf("错误，正确答案: %s (%s)，你选的 %s 是 %s",
+				currentKana, strings.Join(kanaToRomaji[currentKana], "/"),
+				options[i], strings.Join(kanaToRomaji[options[i]], "/")))
+		}
+		expected := currentKana
+		if !reverse {
+			expected = romajiLabel(currentKana)
+		}
+		pool.answer(currentKana, given, correct, session.Mistake{Question: question.Text, Answer: given, Expected: expected})
+		statsLabel.SetText(fmt.Sprintf("当前正确率: %.2f%%", stats.Accuracy()))
+	}
+	for i := range buttons {
+		i := i
+		buttons[i] = widget.NewButton("", func() { choose(i) })
+	}
+
+	nextQuestion := func() {
+		if sess.Finished() {
+			// 换成总结后不再响应数字键
+			answered = true
+			session.ShowSummary(w, sess, func(missed []string) {
+				w.Close()
+				showChoiceMode(myApp, missed, mainWin, stats, statsLabel, reverse, session.EachOnce)
+			})
+			return
+		}
+		progress.SetText(sess.Progress())
+		feedback.SetText("")
+		answered = false
+		currentKana = pool.next()
+		options = append(distractors(currentKana, choiceCount-1), currentKana)
+		rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+
+		if reverse {
+			question.SetText(fmt.Sprintf("哪个假名的罗马音是 %s ？", romajiLabel(currentKana)))
+		} else {
+			question.SetText(fmt.Sprintf("%s 的罗马音是？", currentKana))
+		}
+		for i, b := range buttons {
+			if i >= len(options) {
+				b.SetText("")
+				b.Disable()
+				continue
+			}
+			label := options[i]
+			if !reverse {
+				label = romajiLabel(label)
+			}
+			b.SetText(fmt.Sprintf("%d. %s", i+1, label))
+			b.Enable()
+		}
+	}
+
+	nextBtn := widget.NewButton("下一题", func() {
+		nextQuestion()
+	})
+
+	backBtn := widget.NewButton("返回主界面", func() {
+		w.Close()
+	})
+
+	w.Canvas().SetOnTypedRune(func(r rune) {
+		if r >= '1' && r < '1'+choiceCount {
+			choose(int(r - '1'))
+		}
+	})
+	w.Canvas().SetOnTypedKey(func(e *fyne.KeyEvent) {
+		if (e.Name == fyne.KeyReturn || e.Name == fyne.KeyEnter) && answered && !sess.Finished() {
+			nextQuestion()
+		}
+	})
+
+	choiceGrid := container.NewGridWithColumns(2)
+	for _, b := range buttons {
+		choiceGrid.Add(b)
+	}
+
+	w.SetContent(container.NewVBox(
+		progress,
+		question,
+		choiceGrid,
+		feedback,
+		container.NewHBox(nextBtn),
+		hint,
+		backBtn,
+	))
+	w.Resize(fyne.NewSize(400, 300))
+	nextQuestion()
+	w.Show()
+}
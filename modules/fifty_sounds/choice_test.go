@@ -0,0 +1,67 @@
+package fifty_sounds
+
+import "testing"
+
+// near 判断 k 是否与 kana 同一行或者字形相近
+func near(k, kana string) bool {
+	for _, g := range confusionGroups {
+		if contains(g, k) && contains(g, kana) {
+			return true
+		}
+	}
+	for _, line := range gojuon {
+		inLine := func(s string) bool { return contains(line.hiragana, s) || contains(line.katakana, s) }
+		if inLine(k) && inLine(kana) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDistractors(t *testing.T) {
+	var all []string
+	for _, line := range gojuon {
+		if !line.extended {
+			all = append(all, line.hiragana...)
+			all = append(all, line.katakana...)
+		}
+	}
+	for _, kana := range append(all, "ティ", "ウォ") {
+		for i := 0; i < 5; i++ {
+			res := distractors(kana, choiceCount-1)
+			if len(res) != choiceCount-1 {
+				t.Fatalf("distractors(%s) = %v, want %d", kana, res, choiceCount-1)
+			}
+			for j, k := range res {
+				if k == kana || isHiragana(k) != isHiragana(kana) {
+					t.Errorf("distractors(%s) = %v: bad option %s", kana, res, k)
+				}
+				if sameSound(k, kana) || sameSound(kana, k) {
+					t.Errorf("distractors(%s) = %v: %s sounds the same", kana, res, k)
+				}
+				for _, other := range res[:j] {
+					if other == k || sameSound(k, other) {
+						t.Errorf("distractors(%s) = %v: %s repeats %s", kana, res, k, other)
+					}
+				}
+			}
+		}
+	}
+}
+
+// 同一行和字形相近的假名足够时，干扰项都从中选取
+func TestDistractorsNear(t *testing.T) {
+	for _, kana := range []string{"あ", "ぬ", "ね", "ク", "シ", "ン", "ティ"} {
+		for i := 0; i < 5; i++ {
+			for _, k := range distractors(kana, choiceCount-1) {
+				if !near(k, kana) {
+					t.Errorf("distractors(%s): %s is neither in the same row nor a look-alike", kana, k)
+				}
+				// ティ 也读作 ti，不能用 チ 作干扰项
+				if kana == "ティ" && k == "チ" {
+					t.Errorf("distractors(ティ) contains チ")
+				}
+			}
+		}
+	}
+}
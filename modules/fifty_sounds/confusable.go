@@ -0,0 +1,314 @@
+package fifty_sounds
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/history"
+	"FiftySound/modules/session"
+)
+
+// ======================= 模式四： 易混假名辨析 =======================
+// 给出罗马音，从一组字形相近的假名中选出正确的一个。
+// 题目来自整理好的易混假名表，以及模式一中自己答错时混淆过的假名；
+// 每对假名的辨析正确率根据答题记录统计，正确率低的组出现得更多
+
+// confusionGroups 字形相近、容易认错的假名，组内任意两个假名构成一对
+var confusionGroups = [][]string{
+	{"シ", "ツ"}, {"ソ", "ン"}, {"シ", "ン"}, {"ツ", "ソ"},
+	{"ぬ", "め"}, {"れ", "わ", "ね"}, {"ク", "ケ", "タ"},
+	{"さ", "ち"}, {"き", "さ"}, {"は", "ほ"}, {"る", "ろ"}, {"あ", "お"}, {"い", "り"}, {"ま", "も"},
+	{"コ", "ユ"}, {"ウ", "ワ", "フ"}, {"チ", "テ"}, {"ノ", "メ"}, {"ア", "マ"}, {"ス", "ヌ"}, {"ル", "レ"},
+}
+
+// confusableDrill 模式四的题目和各对假名的辨析正确率
+type confusableDrill struct {
+	groups  [][]string
+	pairs   map[[2]string]*history.Accuracy
+	log     *history.Log
+	sess    *session.Session // 以组为单位计算题量
+	group   []string         // 当前题目所在的组
+	last    string
+	shownAt time.Time
+}
+
+// newConfusableDrill 只保留 targets 中的假名；至少两个假名都被选中的组才会出题
+func newConfusableDrill(log *history.Log, targets []string, length session.Length) *confusableDrill {
+	d := &confusableDrill{
+		pairs: make(map[[2]string]*history.Accuracy),
+		log:   log,
+	}
+	covered := make(map[[2]string]bool)
+	add := func(group []string) {
+		var g []string
+		for _, k := range group {
+			if contains(targets, k) {
+				g = append(g, k)
+			}
+		}
+		if len(g) < 2 {
+			return
+		}
+		for i := range g {
+			for _, o := range g[i+1:] {
+				covered[pairKey(g[i], o)] = true
+			}
+		}
+		d.groups = append(d.groups, g)
+	}
+	for _, g := range confusionGroups {
+		add(g)
+	}
+	// 自己在模式一中混淆过的假名；罗马音相同的（如 じ 和 ぢ）无法用罗马音区分，跳过
+	mined := confusedPairs(log.Query(history.Filter{Module: history.ModuleKana, Mode: modeOneName}))
+	for _, p := range mined {
+		if !covered[pairKey(p.A, p.B)] && !sameSound(p.A, p.B) {
+			add([]string{p.A, p.B})
+		}
+	}
+
+	for _, r := range log.Query(history.Filter{Module: history.ModuleKana, Mode: modeFourName}) {
+		d.count(r)
+	}
+	d.sess = session.New(length, len(d.groups))
+	return d
+}
+
+// sameSound 两个假名是否有相同的罗马音写法
+func sameSound(a, b string) bool {
+	for _, r := range kanaToRomaji[b] {
+		if checkRomaji(a, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// count 把一次作答计入各对假名：答对说明区分开了组内所有其它假名，
+// 答错只计入目标假名和误选的假名这一对
+func (d *confusableDrill) count(r history.Record) {
+	add := func(other string, correct bool) {
+		key := pairKey(r.Item, other)
+		acc := d.pairs[key]
+		if acc == nil {
+			acc = &history.Accuracy{}
+			d.pairs[key] = acc
+		}
+		acc.Total++
+		if correct {
+			acc.Correct++
+		}
+	}
+	if !r.Correct {
+		add(r.Answer, false)
+		return
+	}
+	for _, o := range strings.Split(r.Group, "/") {
+		if o != r.Item {
+			add(o, true)
+		}
+	}
+}
+
+// weight 组内最难区分的一对假名越容易错，这一组越常出现；没练过的按 50% 计算
+func (d *confusableDrill) weight(group []string) float64 {
+	worst := 1.0
+	for i := range group {
+		for _, o := range group[i+1:] {
+			rate := 0.5
+			if acc := d.pairs[pairKey(group[i], o)]; acc != nil && acc.Total > 0 {
+				rate = acc.Rate() / 100
+			}
+			worst = min(worst, rate)
+		}
+	}
+	return 1 + 4*(1-worst)
+}
+
+// groupKey 组在题量统计中的标识，如 "シ/ツ"
+func groupKey(g []string) string {
+	return strings.Join(g, "/")
+}
+
+// next 按权重抽一组，再从组内选一个目标假名，不与上一题相同；选项打乱顺序
+func (d *confusableDrill) next() (target string, options []string) {
+	var keys []string
+	for _, g := range d.groups {
+		keys = append(keys, groupKey(g))
+	}
+	pending := d.sess.Pending(keys)
+	var groups [][]string
+	for _, g := range d.groups {
+		if contains(pending, groupKey(g)) {
+			groups = append(groups, g)
+		}
+	}
+
+	total := 0.0
+	for _, g := range groups {
+		total += d.weight(g)
+	}
+	x := rand.Float64() * total
+	group := groups[len(groups)-1]
+	for _, g := range groups {
+		if x -= d.weight(g); x < 0 {
+			group = g
+			break
+		}
+	}
+
+	d.group = group
+	options = append([]string(nil), group...)
+	rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+	target = options[rand.Intn(len(options))]
+	if target == d.last {
+		target = options[(indexOf(options, target)+1)%len(options)]
+	}
+	d.last = target
+	d.shownAt = time.Now()
+	return target, options
+}
+
+// answer 记录一次作答，group 记为 "シ/ツ" 的形式，以便之后统计每对假名
+func (d *confusableDrill) answer(target, chosen string, options []string) bool {
+	r := history.Record{
+		Module:  history.ModuleKana,
+		Mode:    modeFourName,
+		Item:    target,
+		Group:   strings.Join(options, "/"),
+		Answer:  chosen,
+		Correct: chosen == target,
+		Latency: time.Since(d.shownAt),
+	}
+	d.log.Add(r)
+	d.count(r)
+	d.sess.Add(groupKey(d.group), r.Correct, session.Mistake{
+		Question: kanaToRomaji[target][0],
+		Answer:   chosen,
+		Expected: target,
+	})
+	return r.Correct
+}
+
+// report 本次练习涉及的各对假名的辨析正确率，从低到高排列
+func (d *confusableDrill) report() string {
+	type line struct {
+		pair [2]string
+		acc  history.Accuracy
+	}
+	var lines []line
+	for _, g := range d.groups {
+		for i := range g {
+			for _, o := range g[i+1:] {
+				if acc := d.pairs[pairKey(g[i], o)]; acc != nil && acc.Total > 0 {
+					lines = append(lines, line{pairKey(g[i], o), *acc})
+				}
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return "还没有辨析记录"
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].acc.Rate() < lines[j].acc.Rate()
+	})
+	var b strings.Builder
+	b.WriteString("各对假名的辨析正确率：")
+	for _, l := range lines {
+		fmt.Fprintf(&b, "\n%s ↔ %s: %.0f%% (%d/%d)", l.pair[0], l.pair[1], l.acc.Rate(), l.acc.Correct, l.acc.Total)
+	}
+	return b.String()
+}
+
+func indexOf(arr []string, t string) int {
+	for i, a := range arr {
+		if a == t {
+			return i
+		}
+	}
+	return -1
+}
+
+func showModeFour(myApp fyne.App, targets []string, mainWin fyne.Window, stats *Stats, statsLabel *widget.Label, length session.Length) {
+	drill := newConfusableDrill(history.Open(myApp), targets, length)
+	if len(drill.groups) == 0 {
+		dialog.ShowInformation("提示", "所选假名中没有容易混淆的假名，请多选择一些假名（如 シ、ツ、ソ、ン）。", mainWin)
+		return
+	}
+
+	w := myApp.NewWindow("模式四")
+	countLabel := widget.NewLabel(fmt.Sprintf("共 %d 组易混假名", len(drill.groups)))
+	progress := widget.NewLabel("")
+	question := widget.NewLabel("")
+	choices := container.NewHBox()
+	feedback := widget.NewLabel("")
+	report := widget.NewLabel(drill.report())
+
+	nextQuestion := func() {
+		if drill.sess.Finished() {
+			session.ShowSummary(w, drill.sess, func(missed []string) {
+				// 重练答错的组：只选这些组里的假名
+				var kana []string
+				for _, k := range missed {
+					kana = append(kana, strings.Split(k, "/")...)
+				}
+				w.Close()
+				showModeFour(myApp, kana, mainWin, stats, statsLabel, session.EachOnce)
+			})
+			return
+		}
+		progress.SetText(drill.sess.Progress())
+		feedback.SetText("")
+		target, options := drill.next()
+		question.SetText(fmt.Sprintf("哪个假名的罗马音是 %s ？", kanaToRomaji[target][0]))
+
+		var buttons []*widget.Button
+		choices.RemoveAll()
+		for _, opt := range options {
+			opt := opt
+			btn := widget.NewButton(opt, func() {
+				for _, b := range buttons {
+					b.Disable()
+				}
+				stats.Total++
+				if drill.answer(target, opt, options) {
+					stats.Correct++
+					feedback.SetText("正确")
+				} else {
+					feedback.SetText(fmt.Sprintf("错误，%s 是 %s，%s 是 %s",
+						target, strings.Join(kanaToRomaji[target], "/"), opt, strings.Join(kanaToRomaji[opt], "/")))
+				}
+				report.SetText(drill.report())
+				statsLabel.SetText(fmt.Sprintf("当前正确率: %.2f%%", stats.Accuracy()))
+			})
+			buttons = append(buttons, btn)
+			choices.Add(btn)
+		}
+	}
+
+	nextBtn := widget.NewButton("下一题", func() {
+		nextQuestion()
+	})
+
+	backBtn := widget.NewButton("返回主界面", func() {
+		w.Close()
+	})
+
+	w.SetContent(container.NewBorder(
+		container.NewVBox(countLabel, progress, question, choices, feedback, nextBtn),
+		backBtn,
+		nil, nil,
+		container.NewVScroll(report),
+	))
+	w.Resize(fyne.NewSize(400, 480))
+	nextQuestion()
+	w.Show()
+}
@@ -0,0 +1,101 @@
+package fifty_sounds
+
+import (
+	"sort"
+
+	"FiftySound/modules/history"
+)
+
+// ======================= 统计用的对外接口：五十音表、易混假名 =======================
+
+// KanaRow 是五十音表中的一行
+type KanaRow struct {
+	Name     string // 如 "ka行"
+	Hiragana []string
+	Katakana []string
+}
+
+// KanaRows 按五十音表顺序返回所有行
+func KanaRows() []KanaRow {
+	rows := make([]KanaRow, 0, len(gojuon))
+	for _, line := range gojuon {
+		rows = append(rows, KanaRow{
+			Name:     line.label(),
+			Hiragana: append([]string(nil), line.hiragana...),
+			Katakana: append([]string(nil), line.katakana...),
+		})
+	}
+	return rows
+}
+
+// ConfusedPair 是一对被混淆的假名，Count 为两个方向混淆次数之和
+type ConfusedPair struct {
+	A, B  string
+	Count int
+}
+
+// ConfusedPairs 从答题记录的错题中找出被混淆的假名对，按次数从多到少排列
+func ConfusedPairs(log *history.Log) []ConfusedPair {
+	return confusedPairs(log.Query(history.Filter{Module: history.ModuleKana}))
+}
+
+// confusedPairs 统计 records 中的错题混淆了哪些假名
+func confusedPairs(records []history.Record) []ConfusedPair {
+	counts := make(map[[2]string]int)
+	for _, r := range records {
+		// 单词朗读的错误不对应单个假名
+		if r.Correct || r.Mode == modeThreeName {
+			continue
+		}
+		mistaken := mistakenKana(r.Item, r.Answer)
+		if mistaken == "" || mistaken == r.Item {
+			continue
+		}
+		counts[pairKey(r.Item, mistaken)]++
+	}
+
+	res := make([]ConfusedPair, 0, len(counts))
+	for k, n := range counts {
+		res = append(res, ConfusedPair{A: k[0], B: k[1], Count: n})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].A+res[i].B < res[j].A+res[j].B
+	})
+	return res
+}
+
+// mistakenKana 推断学习者把 kana 误认成了哪个假名：
+// 手写模式的作答本身就是假名；罗马音模式按作答的罗马音在同一种假名中反查
+func mistakenKana(kana, answer string) string {
+	if _, ok := kanaToRomaji[answer]; ok {
+		return answer
+	}
+	return romajiToKana(answer, isHiragana(kana))
+}
+
+// romajiToKana 按五十音表顺序查找罗马音对应的平假名或片假名，找不到时返回空串
+func romajiToKana(romaji string, hira bool) string {
+	for _, line := range gojuon {
+		list := line.katakana
+		if hira {
+			list = line.hiragana
+		}
+		for _, k := range list {
+			if checkRomaji(k, romaji) {
+				return k
+			}
+		}
+	}
+	return ""
+}
+
+// pairKey 一对假名的键，与顺序无关
+func pairKey(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
@@ -0,0 +1,52 @@
+package fifty_sounds
+
+import (
+	"reflect"
+	"testing"
+
+	"FiftySound/modules/history"
+)
+
+func TestMistakenKana(t *testing.T) {
+	tests := []struct {
+		kana, answer, want string
+	}{
+		{"め", "ぬ", "ぬ"}, // 手写模式的作答就是假名
+		{"め", "nu", "ぬ"},
+		{"シ", "tsu", "ツ"},
+		{"シ", "TU", "ツ"},
+		{"ち", "sa", "さ"},
+		{"め", "xyz", ""},
+	}
+	for _, tt := range tests {
+		if got := mistakenKana(tt.kana, tt.answer); got != tt.want {
+			t.Errorf("mistakenKana(%q, %q) = %q, want %q", tt.kana, tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestConfusedPairs(t *testing.T) {
+	miss := func(mode, item, answer string) history.Record {
+		return history.Record{Module: history.ModuleKana, Mode: mode, Item: item, Answer: answer}
+	}
+	records := []history.Record{
+		// 两个方向的混淆计入同一对
+		miss(modeOneName, "め", "nu"),
+		miss(modeOneName, "ぬ", "me"),
+		miss(modeTwoName, "め", "ぬ"),
+		miss(modeOneName, "シ", "tsu"),
+		// 答对的、单词朗读的、无法反查的和答成自己的都不计
+		{Module: history.ModuleKana, Mode: modeOneName, Item: "め", Answer: "me", Correct: true},
+		miss(modeThreeName, "しつ", "shitsu"),
+		miss(modeThreeName, "め", "nu"),
+		miss(modeOneName, "め", "xyz"),
+		miss(modeTwoName, "め", "め"),
+	}
+	want := []ConfusedPair{
+		{A: "ぬ", B: "め", Count: 3},
+		{A: "シ", B: "ツ", Count: 1},
+	}
+	if got := confusedPairs(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("confusedPairs = %+v, want %+v", got, want)
+	}
+}
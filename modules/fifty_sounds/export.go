@@ -0,0 +1,170 @@
+package fifty_sounds
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/anki"
+	"FiftySound/modules/appdata"
+	"FiftySound/modules/srs"
+)
+
+// ======================= 导出五十音 =======================
+// 把全部平假名、片假名连同罗马音导出为 CSV、JSON 或 Anki 卡组，可以附带复习进度
+
+// 导出格式
+const (
+	formatCSV  = "CSV"
+	formatJSON = "JSON"
+	formatApkg = "Anki 卡组 (.apkg)"
+)
+
+var formatExt = map[string]string{
+	formatCSV:  ".csv",
+	formatJSON: ".json",
+	formatApkg: ".apkg",
+}
+
+// exportedKana 导出的一个假名
+type exportedKana struct {
+	Kana     string    `json:"假名"`
+	Romaji   []string  `json:"罗马音"`
+	Row      string    `json:"行"`
+	Script   string    `json:"类别"`
+	Progress *srs.Card `json:"复习进度,omitempty"`
+}
+
+// allKana 按五十音图顺序列出全部假名，先平假名后片假名
+func allKana() []exportedKana {
+	var res []exportedKana
+	for _, script := range []string{"平假名", "片假名"} {
+		for _, line := range gojuon {
+			chars := line.hiragana
+			if script == "片假名" {
+				chars = line.katakana
+			}
+			for _, k := range chars {
+				res = append(res, exportedKana{
+					Kana:   k,
+					Romaji: kanaToRomaji[k],
+					Row:    line.label(),
+					Script: script,
+				})
+			}
+		}
+	}
+	return res
+}
+
+// showExportKanaDialog 选择格式和是否包含复习进度，然后选择保存位置
+func showExportKanaDialog(myApp fyne.App, win fyne.Window) {
+	formatSelect := widget.NewSelect([]string{formatCSV, formatJSON, formatApkg}, nil)
+	formatSelect.SetSelected(formatCSV)
+	progressCheck := widget.NewCheck("包含复习进度", nil)
+
+	content := container.NewVBox(
+		widget.NewLabel("导出全部平假名和片假名及其罗马音"),
+		formatSelect,
+		progressCheck,
+	)
+	dialog.ShowCustomConfirm("导出五十音", "导出", "取消", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		var sched *srs.Scheduler
+		if progressCheck.Checked {
+			sched = kanaScheduler(myApp)
+		}
+		format := formatSelect.Selected
+		data, err := exportKana(format, sched)
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		appdata.SaveAs(win, "五十音"+formatExt[format], data)
+	}, win)
+}
+
+// exportKana 按格式生成文件内容，sched 为 nil 时不包含复习进度
+func exportKana(format string, sched *srs.Scheduler) ([]byte, error) {
+	items := allKana()
+	if sched != nil {
+		for i := range items {
+			if c, ok := sched.Card(items[i].Kana); ok {
+				items[i].Progress = &c
+			}
+		}
+	}
+
+	switch format {
+	case formatJSON:
+		return json.MarshalIndent(items, "", "  ")
+	case formatApkg:
+		return kanaApkg(items)
+	}
+	return kanaCSV(items)
+}
+
+func kanaCSV(items []exportedKana) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\xef\xbb\xbf") // BOM，让 Excel 按 UTF-8 打开
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"假名", "罗马音", "其它写法", "行", "类别", "到期时间", "间隔(天)", "遗忘次数"}); err != nil {
+		return nil, err
+	}
+	for _, k := range items {
+		other := ""
+		if len(k.Romaji) > 1 {
+			other = strings.Join(k.Romaji[1:], "/")
+		}
+		rec := []string{k.Kana, displayRomaji(k.Romaji), other, k.Row, k.Script, "", "", ""}
+		if c := k.Progress; c != nil {
+			rec[5] = c.Due.Local().Format("2006-01-02 15:04")
+			rec[6] = fmt.Sprintf("%.1f", c.Interval)
+			rec[7] = fmt.Sprint(c.Lapses)
+		}
+		if err := w.Write(rec); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func kanaApkg(items []exportedKana) ([]byte, error) {
+	deck := anki.Deck{
+		Name:   "FiftySound五十音",
+		Model:  "FiftySound五十音",
+		Fields: []string{"假名", "罗马音", "行"},
+		Front:  "<div style='font-size:64px'>{{假名}}</div>",
+		Back:   "{{FrontSide}}<hr id=answer>{{罗马音}}<br><small>{{行}}</small>",
+	}
+	for _, k := range items {
+		n := anki.ExportNote{
+			Key:    k.Kana,
+			Deck:   []string{k.Script},
+			Fields: []string{k.Kana, displayRomaji(k.Romaji), k.Row},
+		}
+		if k.Progress != nil {
+			n.State = anki.StateFromSRS(*k.Progress, true)
+		}
+		deck.Notes = append(deck.Notes, n)
+	}
+	return anki.WritePackage(deck)
+}
+
+// displayRomaji 显示用的罗马音，即 kanaToRomaji 中的第一个写法
+func displayRomaji(romaji []string) string {
+	if len(romaji) == 0 {
+		return ""
+	}
+	return romaji[0]
+}
@@ -13,13 +13,31 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/history"
+	"FiftySound/modules/mistakes"
+	"FiftySound/modules/session"
+	"FiftySound/modules/srs"
 )
 
 // ======================= 五十音图行定义 =======================
 type gojuonLine struct {
 	romaji   string
-	hiragana []string
+	hiragana []string // 只有片假名的行（外来音、长音）为空
 	katakana []string
+	name     string // 行名，为空时为 romaji + "行"
+	extended bool   // 扩展假名，"全部随机"时不包含
+	// 与 hiragana、katakana 一一对应的标准笔画，格式见 strokes.go；
+	// 浊音、拗音等行为空，笔画由基础假名组合生成，见 kanaStrokes
+	hiraganaStrokes [][]string
+	katakanaStrokes [][]string
+}
+
+func (l gojuonLine) label() string {
+	if l.name != "" {
+		return l.name
+	}
+	return l.romaji + "行"
 }
 
 var gojuon = []gojuonLine{
@@ -28,51 +46,183 @@ var gojuon = []gojuonLine{
 		romaji:   "a",
 		hiragana: []string{"あ", "い", "う", "え", "お"},
 		katakana: []string{"ア", "イ", "ウ", "エ", "オ"},
+		hiraganaStrokes: [][]string{
+			{"20,30 75,26", "45,10 47,50 52,85", "68,40 55,65 38,82 26,75 32,60 55,52 78,56 86,70 78,85 62,92"}, // あ
+			{"25,25 22,55 28,78 35,82", "70,35 78,50 80,65"},                                                    // い
+			{"38,15 60,22", "28,42 55,35 72,45 70,65 50,88"},                                                    // う
+			{"40,12 60,20", "25,40 68,38 30,85 45,68 55,80 82,85"},                                              // え
+			{"18,30 70,28", "42,10 45,60 42,85 28,80 25,70 40,60 65,58 80,68 72,85 58,90", "70,20 82,35"},       // お
+		},
+		katakanaStrokes: [][]string{
+			{"18,20 80,18 60,42 50,45", "48,30 45,60 25,88"},         // ア
+			{"70,12 18,55", "48,38 48,90"},                           // イ
+			{"50,8 50,22", "22,22 22,42", "22,25 78,25 70,60 42,88"}, // ウ
+			{"25,22 75,22", "50,22 50,78", "15,78 85,78"},            // エ
+			{"15,35 85,35", "60,10 60,88 50,82", "58,38 20,80"},      // オ
+		},
 	},
 	{
 		romaji:   "ka",
 		hiragana: []string{"か", "き", "く", "け", "こ"},
 		katakana: []string{"カ", "キ", "ク", "ケ", "コ"},
+		hiraganaStrokes: [][]string{
+			{"15,38 55,32 58,50 52,75 42,85", "35,12 30,50 20,85", "72,25 85,45"}, // か
+			{"25,25 72,20", "22,42 75,37", "40,10 65,60", "35,65 40,82 70,88"},    // き
+			{"65,12 30,50 65,88"}, // く
+			{"22,15 18,55 25,85", "40,38 85,34", "65,12 68,55 55,90"}, // け
+			{"30,25 65,22 58,32", "28,65 40,80 75,78"},                // こ
+		},
+		katakanaStrokes: [][]string{
+			{"20,32 75,30 72,75 62,82", "45,12 42,55 20,88"},    // カ
+			{"20,32 80,28", "15,58 85,52", "45,10 58,90"},       // キ
+			{"40,12 15,48", "35,28 75,28 65,60 30,90"},          // ク
+			{"35,12 12,48", "30,32 85,32", "60,32 55,65 35,90"}, // ケ
+			{"22,25 75,25 75,78", "22,78 75,78"},                // コ
+		},
 	},
 	{
 		romaji:   "sa",
 		hiragana: []string{"さ", "し", "す", "せ", "そ"},
 		katakana: []string{"サ", "シ", "ス", "セ", "ソ"},
+		hiraganaStrokes: [][]string{
+			{"25,30 75,25", "40,10 68,55", "35,68 42,82 70,85"},             // さ
+			{"32,12 30,65 42,85 75,65"},                                     // し
+			{"15,30 85,28", "50,10 52,50 40,55 38,45 52,45 52,60 40,90"},    // す
+			{"15,45 85,40", "68,18 68,62 58,65", "35,15 33,70 42,85 75,85"}, // せ
+			{"35,15 65,12 25,42 75,40 50,55 48,75 65,88"},                   // そ
+		},
+		katakanaStrokes: [][]string{
+			{"12,38 88,38", "32,15 32,62", "68,12 66,60 45,88"}, // サ
+			{"20,18 38,30", "12,42 32,55", "22,85 55,70 82,30"}, // シ
+			{"20,20 75,20 55,55 18,88", "55,55 85,85"},          // ス
+			{"12,42 85,32 62,58", "35,12 35,78 48,86 80,85"},    // セ
+			{"20,25 35,48", "78,20 65,60 30,88"},                // ソ
+		},
 	},
 	{
 		romaji:   "ta",
 		hiragana: []string{"た", "ち", "つ", "て", "と"},
 		katakana: []string{"タ", "チ", "ツ", "テ", "ト"},
+		hiraganaStrokes: [][]string{
+			{"18,30 55,27", "40,12 25,88", "55,50 80,45", "55,70 60,85 82,85"}, // た
+			{"20,30 70,25", "45,10 35,58 55,48 75,55 72,75 45,88"},             // ち
+			{"15,38 55,28 80,40 72,65 45,80"},                                  // つ
+			{"18,25 80,18 50,40 45,65 60,85"},                                  // て
+			{"38,15 48,45", "70,30 35,55 35,78 48,86 78,84"},                   // と
+		},
+		katakanaStrokes: [][]string{
+			{"40,12 15,48", "35,28 75,28 65,60 30,90", "32,45 62,62"}, // タ
+			{"72,12 25,25", "12,45 88,45", "50,22 50,65 35,88"},       // チ
+			{"15,25 28,45", "42,20 52,40", "80,20 65,60 35,88"},       // ツ
+			{"25,20 75,20", "12,42 88,42", "50,42 48,65 30,88"},       // テ
+			{"35,10 35,90", "35,42 75,60"},                            // ト
+		},
 	},
 	{
 		romaji:   "na",
 		hiragana: []string{"な", "に", "ぬ", "ね", "の"},
 		katakana: []string{"ナ", "ニ", "ヌ", "ネ", "ノ"},
+		hiraganaStrokes: [][]string{
+			{"15,28 50,24", "35,10 22,60", "65,30 80,42", "62,45 60,80 50,85 42,78 52,70 70,78 82,88"}, // な
+			{"22,15 18,55 25,85", "45,30 75,28", "45,65 52,78 78,78"},                                  // に
+			{"22,30 45,80", "55,15 40,65 25,80 18,65 35,40 60,30 80,45 78,75 62,85 55,75 70,72 88,88"}, // ぬ
+			{"30,10 28,90", "12,30 30,30 12,75 45,38 70,35 78,60 70,82 55,82 55,70 70,72 88,88"},       // ね
+			{"50,25 35,75 20,65 25,40 50,25 75,35 80,65 55,88"},                                        // の
+		},
+		katakanaStrokes: [][]string{
+			{"12,38 88,38", "52,12 50,60 28,90"},                              // ナ
+			{"25,28 75,28", "12,75 88,75"},                                    // ニ
+			{"20,22 75,22 60,55 20,88", "32,45 80,82"},                        // ヌ
+			{"50,8 50,22", "18,28 78,28 20,72", "50,45 50,92", "62,55 82,72"}, // ネ
+			{"75,12 60,55 20,88"},                                             // ノ
+		},
 	},
 	{
 		romaji:   "ha",
 		hiragana: []string{"は", "ひ", "ふ", "へ", "ほ"},
 		katakana: []string{"ハ", "ヒ", "フ", "ヘ", "ホ"},
+		hiraganaStrokes: [][]string{
+			{"20,15 18,55 25,85", "42,35 82,32", "62,12 62,78 50,85 42,75 55,68 70,75 82,88"}, // は
+			{"15,30 38,28 25,60 35,82 55,82 68,45 70,30 80,60 90,75"},                         // ひ
+			{"42,15 55,25", "50,35 35,65 50,85 45,88", "22,62 12,80", "72,58 85,78"},          // ふ
+			{"12,62 35,35 88,78"}, // へ
+			{"20,15 18,55 25,85", "42,22 80,20", "42,45 80,43", "62,20 62,78 50,85 42,75 55,68 70,75 82,88"}, // ほ
+		},
+		katakanaStrokes: [][]string{
+			{"38,30 15,75", "60,28 88,78"},                                     // ハ
+			{"25,40 78,30", "25,12 25,80 35,85 80,82"},                         // ヒ
+			{"18,22 78,22 65,60 30,88"},                                        // フ
+			{"12,62 35,35 88,78"},                                              // ヘ
+			{"15,35 85,35", "50,10 50,80 40,75", "30,52 15,78", "70,52 85,78"}, // ホ
+		},
 	},
 	{
 		romaji:   "ma",
 		hiragana: []string{"ま", "み", "む", "め", "も"},
 		katakana: []string{"マ", "ミ", "ム", "メ", "モ"},
+		hiraganaStrokes: [][]string{
+			{"22,25 78,22", "22,45 78,42", "50,10 50,78 38,85 32,75 45,68 62,75 78,88"},             // ま
+			{"28,20 55,18 30,70 20,80 15,65 40,55 70,62 85,72", "70,38 65,75 58,90"},                // み
+			{"15,35 55,32", "35,12 35,65 25,70 22,60 35,55 38,75 50,85 82,82 82,60", "70,25 82,35"}, // む
+			{"25,30 45,80", "60,15 40,65 25,80 18,65 35,40 60,30 80,45 78,75 60,88"},                // め
+			{"45,10 35,55 40,80 60,85 78,70 75,45", "20,35 65,32", "18,55 62,52"},                   // も
+		},
+		katakanaStrokes: [][]string{
+			{"15,25 85,25 50,65", "35,48 65,80"},                      // マ
+			{"28,18 70,28", "32,42 68,52", "25,68 75,85"},             // ミ
+			{"45,12 15,80 80,72", "62,52 85,88"},                      // ム
+			{"72,12 52,55 18,88", "28,35 78,78"},                      // メ
+			{"22,22 78,22", "15,48 85,48", "45,22 45,80 55,86 85,85"}, // モ
+		},
 	},
 	{
 		romaji:   "ya",
 		hiragana: []string{"や", "ゆ", "よ"},
 		katakana: []string{"ヤ", "ユ", "ヨ"},
+		hiraganaStrokes: [][]string{
+			{"20,45 60,30 82,40 78,55 62,60", "45,12 52,25", "30,18 60,90"},          // や
+			{"22,25 15,60 25,80 40,50 65,38 85,55 75,75 55,70", "55,15 58,60 45,90"}, // ゆ
+			{"52,35 80,33", "50,12 52,75 38,85 30,75 42,68 62,75 82,88"},             // よ
+		},
+		katakanaStrokes: [][]string{
+			{"12,40 85,28 65,52", "35,12 50,88"},                // ヤ
+			{"25,30 70,30 68,78", "12,78 88,78"},                // ユ
+			{"22,20 75,20 75,85", "25,52 75,52", "22,85 75,85"}, // ヨ
+		},
 	},
 	{
 		romaji:   "ra",
 		hiragana: []string{"ら", "り", "る", "れ", "ろ"},
 		katakana: []string{"ラ", "リ", "ル", "レ", "ロ"},
+		hiraganaStrokes: [][]string{
+			{"40,12 55,22", "28,30 22,65 45,50 72,58 70,78 40,90"},          // ら
+			{"28,18 25,55 32,60", "68,15 72,50 62,80 45,90"},                // り
+			{"25,20 70,18 25,62 60,52 80,68 70,85 50,88 42,78 55,72 65,80"}, // る
+			{"30,10 28,90", "12,30 30,30 12,75 45,40 65,32 62,78 88,78"},    // れ
+			{"25,20 70,18 25,62 60,52 80,68 70,85 45,90"},                   // ろ
+		},
+		katakanaStrokes: [][]string{
+			{"25,18 75,18", "20,40 80,40 65,70 30,90"},          // ラ
+			{"30,15 30,60", "70,12 70,50 40,90"},                // リ
+			{"35,15 35,50 15,85", "60,12 60,82 88,55"},          // ル
+			{"30,12 30,85 85,55"},                               // レ
+			{"22,22 22,82", "22,22 78,22 78,82", "22,82 78,82"}, // ロ
+		},
 	},
 	{
 		romaji:   "wa",
 		hiragana: []string{"わ", "を", "ん"},
 		katakana: []string{"ワ", "ヲ", "ン"},
+		hiraganaStrokes: [][]string{
+			{"30,10 28,90", "12,30 30,30 12,75 45,40 70,35 82,55 75,78 55,88"},    // わ
+			{"20,28 70,25", "45,10 28,50 55,45 65,50", "70,45 25,75 40,90 75,88"}, // を
+			{"55,12 20,85 45,50 58,60 60,80 85,65"},                               // ん
+		},
+		katakanaStrokes: [][]string{
+			{"22,22 22,45", "22,22 78,22 70,60 38,88"},          // ワ
+			{"20,22 78,22", "20,48 75,48", "78,22 65,62 30,88"}, // ヲ
+			{"15,25 35,40", "22,85 55,70 85,25"},                // ン
+		},
 	},
 
 	// 浊音・半濁音 (Voiced and Semi-Voiced Sounds)
@@ -158,6 +308,91 @@ var gojuon = []gojuonLine{
 		hiragana: []string{"りゃ", "りゅ", "りょ"},
 		katakana: []string{"リャ", "リュ", "リョ"},
 	},
+
+	// 扩展假名 (Extended Kana)：小写假名、促音、长音、ゔ、古假名
+	{
+		romaji:   "xa",
+		name:     "小写假名",
+		hiragana: []string{"ぁ", "ぃ", "ぅ", "ぇ", "ぉ", "ゃ", "ゅ", "ょ", "ゎ"},
+		katakana: []string{"ァ", "ィ", "ゥ", "ェ", "ォ", "ャ", "ュ", "ョ", "ヮ"},
+		extended: true,
+	},
+	{
+		romaji:   "xtsu",
+		name:     "促音",
+		hiragana: []string{"っ"},
+		katakana: []string{"ッ"},
+		extended: true,
+	},
+	{
+		romaji:   "-",
+		name:     "长音",
+		katakana: []string{"ー"},
+		extended: true,
+		katakanaStrokes: [][]string{
+			{"12,50 88,50"}, // ー
+		},
+	},
+	{
+		romaji:   "vu",
+		name:     "ゔ",
+		hiragana: []string{"ゔ"},
+		katakana: []string{"ヴ"},
+		extended: true,
+	},
+	{
+		romaji:   "wi",
+		name:     "古假名",
+		hiragana: []string{"ゐ", "ゑ"},
+		katakana: []string{"ヰ", "ヱ"},
+		extended: true,
+		hiraganaStrokes: [][]string{
+			{"22,28 45,18 38,50 28,78 40,86 58,70 70,45 80,62 74,82 60,82 56,66 64,52"}, // ゐ
+			{"30,15 68,12 38,38 72,40 25,64 52,55 50,86 36,78 56,70 80,86"},             // ゑ
+		},
+		katakanaStrokes: [][]string{
+			{"20,32 80,32", "35,32 32,70", "12,62 88,62", "66,12 66,90"}, // ヰ
+			{"20,20 75,20 55,45", "50,40 50,82", "12,82 88,82"},          // ヱ
+		},
+	},
+
+	// 外来音 (Foreign Sounds)，只有片假名
+	{
+		romaji:   "fa",
+		name:     "外来音 fa",
+		katakana: []string{"ファ", "フィ", "フェ", "フォ"},
+		extended: true,
+	},
+	{
+		romaji:   "va",
+		name:     "外来音 va",
+		katakana: []string{"ヴァ", "ヴィ", "ヴェ", "ヴォ"},
+		extended: true,
+	},
+	{
+		romaji:   "ti",
+		name:     "外来音 ti",
+		katakana: []string{"ティ", "ディ", "トゥ", "ドゥ"},
+		extended: true,
+	},
+	{
+		romaji:   "wi",
+		name:     "外来音 wi",
+		katakana: []string{"ウィ", "ウェ", "ウォ"},
+		extended: true,
+	},
+	{
+		romaji:   "she",
+		name:     "外来音 she",
+		katakana: []string{"シェ", "チェ", "ジェ", "イェ"},
+		extended: true,
+	},
+	{
+		romaji:   "tsa",
+		name:     "外来音 tsa",
+		katakana: []string{"ツァ", "ツィ", "ツェ", "ツォ"},
+		extended: true,
+	},
 }
 
 // ======================= kanaToRomaji 定义 =======================
@@ -175,7 +410,7 @@ var kanaToRomaji = map[string][]string{
 	"ば": {"ba"}, "び": {"bi"}, "ぶ": {"bu"}, "べ": {"be"}, "ぼ": {"bo"},
 	"ぱ": {"pa"}, "ぴ": {"pi"}, "ぷ": {"pu"}, "ぺ": {"pe"}, "ぽ": {"po"},
 	"ま": {"ma"}, "み": {"mi"}, "む": {"mu"}, "め": {"me"}, "も": {"mo"},
-	"や": {"ya"}, "ゆ": {"yu"}, "よ": {"yo"}, "ゃ": {"ya"}, "ゅ": {"yu"}, "ょ": {"yo"},
+	"や": {"ya"}, "ゆ": {"yu"}, "よ": {"yo"},
 	"ら": {"ra"}, "り": {"ri"}, "る": {"ru"}, "れ": {"re"}, "ろ": {"ro"},
 	"わ": {"wa"}, "を": {"o(wo)", "o", "wo"}, "ん": {"n"},
 	"きゃ": {"kya"}, "きゅ": {"kyu"}, "きょ": {"kyo"},
@@ -203,7 +438,7 @@ var kanaToRomaji = map[string][]string{
 	"バ": {"ba"}, "ビ": {"bi"}, "ブ": {"bu"}, "ベ": {"be"}, "ボ": {"bo"},
 	"パ": {"pa"}, "ピ": {"pi"}, "プ": {"pu"}, "ペ": {"pe"}, "ポ": {"po"},
 	"マ": {"ma"}, "ミ": {"mi"}, "ム": {"mu"}, "メ": {"me"}, "モ": {"mo"},
-	"ヤ": {"ya"}, "ユ": {"yu"}, "ヨ": {"yo"}, "ャ": {"ya"}, "ュ": {"yu"}, "ョ": {"yo"},
+	"ヤ": {"ya"}, "ユ": {"yu"}, "ヨ": {"yo"},
 	"ラ": {"ra"}, "リ": {"ri"}, "ル": {"ru"}, "レ": {"re"}, "ロ": {"ro"},
 	"ワ": {"wa"}, "ヲ": {"o(wo)", "o", "wo"}, "ン": {"n"},
 	"キャ": {"kya"}, "キュ": {"kyu"}, "キョ": {"kyo"},
@@ -217,11 +452,47 @@ var kanaToRomaji = map[string][]string{
 	"ピャ": {"pya"}, "ピュ": {"pyu"}, "ピョ": {"pyo"},
 	"ミャ": {"mya"}, "ミュ": {"myu"}, "ミョ": {"myo"},
 	"リャ": {"rya"}, "リュ": {"ryu"}, "リョ": {"ryo"},
+
+	// 扩展假名：小写假名按读音或 x/l 加读音输入
+	"ぁ": {"a(xa)", "a", "xa", "la"}, "ぃ": {"i(xi)", "i", "xi", "li"}, "ぅ": {"u(xu)", "u", "xu", "lu"},
+	"ぇ": {"e(xe)", "e", "xe", "le"}, "ぉ": {"o(xo)", "o", "xo", "lo"},
+	"ゃ": {"ya(xya)", "ya", "xya", "lya"}, "ゅ": {"yu(xyu)", "yu", "xyu", "lyu"}, "ょ": {"yo(xyo)", "yo", "xyo", "lyo"},
+	"ゎ": {"wa(xwa)", "wa", "xwa", "lwa"},
+	"っ": {"xtsu", "xtu", "ltsu", "ltu"},
+	"ゔ": {"vu"}, "ゐ": {"wi(wyi)", "wi", "wyi"}, "ゑ": {"we(wye)", "we", "wye"},
+
+	"ァ": {"a(xa)", "a", "xa", "la"}, "ィ": {"i(xi)", "i", "xi", "li"}, "ゥ": {"u(xu)", "u", "xu", "lu"},
+	"ェ": {"e(xe)", "e", "xe", "le"}, "ォ": {"o(xo)", "o", "xo", "lo"},
+	"ャ": {"ya(xya)", "ya", "xya", "lya"}, "ュ": {"yu(xyu)", "yu", "xyu", "lyu"}, "ョ": {"yo(xyo)", "yo", "xyo", "lyo"},
+	"ヮ": {"wa(xwa)", "wa", "xwa", "lwa"},
+	"ッ": {"xtsu", "xtu", "ltsu", "ltu"},
+	"ー": {"-"},
+	"ヴ": {"vu"}, "ヰ": {"wi(wyi)", "wi", "wyi"}, "ヱ": {"we(wye)", "we", "wye"},
+	"ファ": {"fa"}, "フィ": {"fi"}, "フェ": {"fe"}, "フォ": {"fo"},
+	"ヴァ": {"va"}, "ヴィ": {"vi"}, "ヴェ": {"ve"}, "ヴォ": {"vo"},
+	// 常用写法在前，括号中是输入法的写法，两种都可以作答
+	"ティ": {"ti(thi)", "ti", "thi"}, "ディ": {"di(dhi)", "di", "dhi"},
+	"トゥ": {"tu(twu)", "tu", "twu"}, "ドゥ": {"du(dwu)", "du", "dwu"},
+	"ウィ": {"wi"}, "ウェ": {"we"}, "ウォ": {"wo(who)", "wo", "who"},
+	"シェ": {"she", "sye"}, "チェ": {"che", "tye"}, "ジェ": {"je", "zye"}, "イェ": {"ye"},
+	"ツァ": {"tsa"}, "ツィ": {"tsi"}, "ツェ": {"tse"}, "ツォ": {"tso"},
 }
 
 // ======================= 其它数据 & 函数 =======================
+// selectedChars 选择假名范围中选中的假名，保存在上次的选择中（见 presets.go）
 var selectedChars []string
 
+// 练习模式
+const (
+	modeOneName   = "模式一: 假名 => 罗马音"
+	modeTwoName   = "模式二: 罗马音 => 假名手写"
+	modeThreeName = "模式三: 单词 => 罗马音"
+	modeFourName  = "模式四: 易混假名辨析"
+	modeFiveName  = "模式五: 假名 => 罗马音 (四选一)"
+	modeSixName   = "模式六: 罗马音 => 假名 (四选一)"
+	modeSevenName = "模式七: 限时冲刺"
+)
+
 type Stats struct {
 	Total   int
 	Correct int
@@ -243,7 +514,7 @@ func ShowFiftySounds(myApp fyne.App, w fyne.Window) {
 	rand.Seed(time.Now().UnixNano())
 
 	// 2) 下拉选择模式
-	modeSelect := widget.NewSelect([]string{"模式一: 假名 => 罗马音", "模式二: 罗马音 => 假名手写"}, func(string) {})
+	modeSelect := widget.NewSelect([]string{modeOneName, modeTwoName, modeThreeName, modeFourName, modeFiveName, modeSixName, modeSevenName}, func(string) {})
 	modeSelect.PlaceHolder = "请点击下拉框，选择你想要的模式"
 
 	// 3) 平假名、片假名复选框
@@ -252,13 +523,53 @@ func ShowFiftySounds(myApp fyne.App, w fyne.Window) {
 	katakanaCheck := widget.NewCheck("片假名", nil)
 	katakanaCheck.SetChecked(true)
 
+	// 恢复上次的选择；之后每次修改都记下来，并清除预设框中的预设名
+	current := func() Preset {
+		return Preset{
+			Chars:    selectedChars,
+			Hiragana: hiraganaCheck.Checked,
+			Katakana: katakanaCheck.Checked,
+			Mode:     modeSelect.Selected,
+		}
+	}
+	applying := false
+	var presets *presetBar
+	onChanged := func() {
+		if applying {
+			return
+		}
+		saveSelection(myApp, current())
+		presets.setSelected("")
+	}
+	apply := func(p Preset) {
+		applying = true
+		selectedChars = append([]string(nil), p.Chars...)
+		hiraganaCheck.SetChecked(p.Hiragana)
+		katakanaCheck.SetChecked(p.Katakana)
+		if contains(modeSelect.Options, p.Mode) {
+			modeSelect.SetSelected(p.Mode)
+		}
+		applying = false
+		saveSelection(myApp, current())
+	}
+	presets = newPresetBar(myApp, newWin, current, apply)
+	if last, ok := loadSelection(myApp); ok {
+		apply(last)
+	}
+	modeSelect.OnChanged = func(string) { onChanged() }
+	hiraganaCheck.OnChanged = func(bool) { onChanged() }
+	katakanaCheck.OnChanged = func(bool) { onChanged() }
+
+	// 每次练习的题量
+	lengthSelect := session.NewLengthSelect(myApp)
+
 	// 4) 统计标签
 	statsLabel := widget.NewLabel("当前正确率: 0.00%")
 
 	// 5) 选择假名范围按钮
 	selectKanaBtn := widget.NewButton("选择假名范围", func() {
 		// 打开“选择假名范围”的新窗口或对话框
-		showKanaSelectionDialog(myApp, newWin)
+		showKanaSelectionDialog(myApp, newWin, onChanged)
 	})
 
 	// 6) “开始”按钮
@@ -274,171 +585,203 @@ func ShowFiftySounds(myApp fyne.App, w fyne.Window) {
 			return
 		}
 		stats := &Stats{}
-		if mode == "模式一: 假名 => 罗马音" {
+		length := session.Load(myApp)
+		switch mode {
+		case modeOneName:
 			// 这里使用 newWin 作为父窗口，或者也可以继续使用 main 窗口
-			showModeOne(myApp, targets, newWin, stats, statsLabel, hiraganaCheck.Checked, katakanaCheck.Checked)
-		} else {
-			showModeTwo(myApp, targets, newWin, hiraganaCheck.Checked, katakanaCheck.Checked)
+			showModeOne(myApp, targets, newWin, stats, statsLabel, hiraganaCheck.Checked, katakanaCheck.Checked, length, false)
+		case modeTwoName:
+			showModeTwo(myApp, targets, newWin, stats, statsLabel, hiraganaCheck.Checked, katakanaCheck.Checked, length)
+		case modeThreeName:
+			showModeThree(myApp, targets, newWin, stats, statsLabel, length)
+		case modeFourName:
+			showModeFour(myApp, targets, newWin, stats, statsLabel, length)
+		case modeFiveName, modeSixName:
+			showChoiceMode(myApp, targets, newWin, stats, statsLabel, mode == modeSixName, length)
+		case modeSevenName:
+			showModeSeven(myApp, targets, newWin, stats, statsLabel, length)
 		}
 	})
 
+	// 导出全部假名和复习进度
+	exportBtn := widget.NewButton("导出五十音", func() {
+		showExportKanaDialog(myApp, newWin)
+	})
+
 	// 7) 布局并设置到 newWin
 	content := container.NewVBox(
 		widget.NewLabel("五十音学习助手 (FiftySound)"),
+		presets.content(),
 		modeSelect,
 		hiraganaCheck,
 		katakanaCheck,
 		selectKanaBtn,
+		lengthSelect,
 		startBtn,
 		statsLabel,
+		exportBtn,
 	)
 	newWin.SetContent(content)
-	newWin.Resize(fyne.NewSize(400, 300))
+	newWin.Resize(fyne.NewSize(560, 340))
 
 	// 8) 显示该子窗口
 	newWin.Show()
 }
 
 // ======================= 选择假名范围 =======================
-func showKanaSelectionDialog(myApp fyne.App, parent fyne.Window) {
-	var lineChecks []*widget.Check
-	var checks []struct {
-		check *widget.Check
-		char  string
-		line  *widget.Check
-	}
+// 平假名和片假名各一张表，行复选框和假名复选框互相独立，
+// 选中的假名直接存入 selectedChars（か 和 カ 分别记录）
+
+// kanaGrid 一种假名的选择表
+type kanaGrid struct {
+	all        *widget.Check
+	lineChecks []*widget.Check // 与 gojuon 对应，该行没有这种假名时为 nil
+	charChecks map[*widget.Check][]*widget.Check
+	chars      map[*widget.Check]string
+	rows       []fyne.CanvasObject // 与 gojuon 对应
+}
 
-	allRandomCheck := widget.NewCheck("全部随机(包含所有五十音)", nil)
-	grid := container.NewVBox(allRandomCheck)
+func newKanaGrid(title string, hira bool) *kanaGrid {
+	g := &kanaGrid{
+		charChecks: make(map[*widget.Check][]*widget.Check),
+		chars:      make(map[*widget.Check]string),
+	}
+	g.all = widget.NewCheck(title+"全选", func(checked bool) {
+		for _, lc := range g.lineChecks {
+			if lc != nil {
+				lc.SetChecked(checked)
+			}
+		}
+	})
 
 	for _, line := range gojuon {
-		lineCheck := widget.NewCheck(line.romaji+"行:", nil)
-		lineChecks = append(lineChecks, lineCheck)
+		chars := line.hiragana
+		if !hira {
+			chars = line.katakana
+		}
+		if len(chars) == 0 {
+			g.lineChecks = append(g.lineChecks, nil)
+			g.rows = append(g.rows, widget.NewLabel(""))
+			continue
+		}
 
+		lineCheck := widget.NewCheck(line.label()+":", nil)
+		var cells []*widget.Check
 		rowItems := []fyne.CanvasObject{lineCheck}
-		var lineCharChecks []*widget.Check
-
-		for _, hchar := range line.hiragana {
-			c := widget.NewCheck(hchar, nil)
-			if contains(selectedChars, hchar) {
-				c.SetChecked(true)
-			}
-			lineCharChecks = append(lineCharChecks, c)
-
-			checks = append(checks, struct {
-				check *widget.Check
-				char  string
-				line  *widget.Check
-			}{c, hchar, lineCheck})
-
+		for _, ch := range chars {
+			c := widget.NewCheck(ch, nil)
+			c.SetChecked(contains(selectedChars, ch))
+			g.chars[c] = ch
+			cells = append(cells, c)
 			rowItems = append(rowItems, c)
 		}
+		g.lineChecks = append(g.lineChecks, lineCheck)
+		g.charChecks[lineCheck] = cells
+		g.rows = append(g.rows, container.NewHBox(rowItems...))
 
-		// 行选中 => 更新本行所有项
-		currentLineCharChecks := lineCharChecks
+		// 行 => 本行所有假名；假名 => 行是否全选
 		lineCheck.OnChanged = func(checked bool) {
-			for _, c := range currentLineCharChecks {
+			for _, c := range cells {
 				c.SetChecked(checked)
 			}
 		}
-
-		// 字项 => 更新行是否全选
-		for _, c := range currentLineCharChecks {
-			c2 := c
-			c2.OnChanged = func(_ bool) {
-				allSelected := true
-				for _, cc := range currentLineCharChecks {
-					if !cc.Checked {
-						allSelected = false
-						break
-					}
-				}
-				lineCheck.OnChanged = nil
-				lineCheck.SetChecked(allSelected)
-				lineCheck.OnChanged = func(checked bool) {
-					for _, ch := range currentLineCharChecks {
-						ch.SetChecked(checked)
-					}
-				}
+		for _, c := range cells {
+			c.OnChanged = func(bool) {
+				setCheckedQuietly(lineCheck, allChecked(cells))
 			}
 		}
-
-		row := container.NewHBox(rowItems...)
-		grid.Add(row)
+		setCheckedQuietly(lineCheck, allChecked(cells))
 	}
+	return g
+}
 
-	// 恢复时，如果没有全选则保持当前状态
-	for i, line := range gojuon {
-		lineCheck := lineChecks[i]
-		hiraganas := line.hiragana
-		allSelected := true
-		for _, hchar := range hiraganas {
-			if !contains(selectedChars, hchar) {
-				allSelected = false
-				break
-			}
-		}
-		lineCheck.OnChanged = nil
-		lineCheck.SetChecked(allSelected)
-		lineCheck.OnChanged = func(checked bool) {
-			var lineCharChecks []*widget.Check
-			for _, c := range checks {
-				if c.line == lineCheck && contains(hiraganas, c.char) {
-					lineCharChecks = append(lineCharChecks, c.check)
-				}
-			}
-			for _, c := range lineCharChecks {
-				c.SetChecked(checked)
+// selected 选中的假名，按五十音表顺序
+func (g *kanaGrid) selected() []string {
+	var res []string
+	for _, lc := range g.lineChecks {
+		for _, c := range g.charChecks[lc] {
+			if c.Checked {
+				res = append(res, g.chars[c])
 			}
 		}
 	}
+	return res
+}
 
-	allRandomCheck.OnChanged = func(checked bool) {
+// setBase 勾选或取消基本假名（不含扩展假名），locked 时禁止修改这些行
+func (g *kanaGrid) setBase(checked, locked bool) {
+	for i, lc := range g.lineChecks {
+		if lc == nil || gojuon[i].extended {
+			continue
+		}
 		if checked {
-			for _, lc := range lineChecks {
-				lc.SetChecked(true)
-				lc.Disable()
-			}
-			for _, item := range checks {
-				item.check.SetChecked(true)
-				item.check.Disable()
-			}
-		} else {
-			for _, lc := range lineChecks {
-				lc.Enable()
-			}
-			for _, item := range checks {
-				item.check.Enable()
+			lc.SetChecked(true)
+		}
+		for _, c := range append([]*widget.Check{lc}, g.charChecks[lc]...) {
+			if locked {
+				c.Disable()
+			} else {
+				c.Enable()
 			}
 		}
 	}
+	if locked {
+		g.all.Disable()
+	} else {
+		g.all.Enable()
+	}
+}
+
+// setCheckedQuietly 修改复选框状态但不触发 OnChanged
+func setCheckedQuietly(c *widget.Check, checked bool) {
+	f := c.OnChanged
+	c.OnChanged = nil
+	c.SetChecked(checked)
+	c.OnChanged = f
+}
+
+func allChecked(checks []*widget.Check) bool {
+	for _, c := range checks {
+		if !c.Checked {
+			return false
+		}
+	}
+	return true
+}
+
+// showKanaSelectionDialog 选择假名范围，确认后调用 onConfirm
+func showKanaSelectionDialog(myApp fyne.App, parent fyne.Window, onConfirm func()) {
+	hiraGrid := newKanaGrid("平假名", true)
+	kataGrid := newKanaGrid("片假名", false)
+
+	allRandomCheck := widget.NewCheck("全部随机(包含所有五十音，不含扩展假名)", func(checked bool) {
+		hiraGrid.setBase(checked, checked)
+		kataGrid.setBase(checked, checked)
+	})
+
+	// 两张表按行并排，同一行的平假名和片假名对齐
+	grid := container.NewVBox(container.NewGridWithColumns(2, hiraGrid.all, kataGrid.all))
+	for i := range gojuon {
+		grid.Add(container.NewGridWithColumns(2, hiraGrid.rows[i], kataGrid.rows[i]))
+	}
 
 	scroll := container.NewScroll(grid)
-	scroll.SetMinSize(fyne.NewSize(600, 400))
+	scroll.SetMinSize(fyne.NewSize(900, 400))
 
 	dialogWin := myApp.NewWindow("选择假名范围") // 原先是 a.NewWindow("选择假名范围")
 	dialogWin.SetContent(container.NewVBox(
+		allRandomCheck,
 		scroll,
 		widget.NewButton("确认", func() {
-			if allRandomCheck.Checked {
-				selectedChars = getAllHiragana()
-				dialogWin.Close()
-				return
-			}
-			var chosen []string
-			for _, item := range checks {
-				if item.check.Checked {
-					chosen = append(chosen, item.char)
-				}
-			}
-			selectedChars = chosen
+			selectedChars = append(hiraGrid.selected(), kataGrid.selected()...)
 			dialogWin.Close()
+			onConfirm()
 		}),
 		widget.NewButton("取消", func() {
 			dialogWin.Close()
 		}),
 	))
-	dialogWin.Resize(fyne.NewSize(700, 500))
+	dialogWin.Resize(fyne.NewSize(1000, 560))
 	dialogWin.Show()
 }
 
@@ -452,91 +795,133 @@ func contains(arr []string, t string) bool {
 	return false
 }
 
-func getAllHiragana() []string {
-	var all []string
-	for _, line := range gojuon {
-		all = append(all, line.hiragana...)
-	}
-	return all
-}
-
-// 根据用户选择的 hiragana/katakana + selectedChars，确定最终 targets
+// 根据 selectedChars 和平假名/片假名两个选项，确定最终 targets
 func getTargets(h, k bool) []string {
 	var result []string
-	if len(selectedChars) == 0 {
-		return result
-	}
-
-	type indexPos struct {
-		lineIndex int
-		colIndex  int
-	}
-	hiraganaIndexMap := make(map[string]indexPos)
-	for li, line := range gojuon {
-		for ci, c := range line.hiragana {
-			hiraganaIndexMap[c] = indexPos{li, ci}
-		}
-	}
-
-	for _, ch := range selectedChars {
-		pos, ok := hiraganaIndexMap[ch]
-		if !ok {
-			continue
-		}
-		lineData := gojuon[pos.lineIndex]
-
-		if h && pos.colIndex < len(lineData.hiragana) {
-			result = append(result, lineData.hiragana[pos.colIndex])
+	for _, line := range gojuon {
+		if h {
+			for _, c := range line.hiragana {
+				if contains(selectedChars, c) {
+					result = append(result, c)
+				}
+			}
 		}
-		if k && pos.colIndex < len(lineData.katakana) {
-			result = append(result, lineData.katakana[pos.colIndex])
+		if k {
+			for _, c := range line.katakana {
+				if contains(selectedChars, c) {
+					result = append(result, c)
+				}
+			}
 		}
 	}
 	return result
 }
 
-// ======================= KanaPool (真随机，不重复一轮) =======================
+// ======================= KanaPool (按间隔重复调度出题) =======================
 type KanaPool struct {
-	items []string
-	index int
+	items    []string
+	sched    *srs.Scheduler
+	log      *history.Log
+	book     *mistakes.Book
+	mode     string
+	sess     *session.Session
+	practice bool // 只练错题
+	judged   bool // 当前题目是否已经判过，同一题多次判题只按第一次计分、记录和安排复习
+	last     string
+	shownAt  time.Time
 }
 
-func newKanaPool(targets []string) *KanaPool {
+func newKanaPool(myApp fyne.App, mode string, targets []string, sess *session.Session) *KanaPool {
 	p := &KanaPool{
 		items: make([]string, len(targets)),
+		sched: kanaScheduler(myApp),
+		log:   history.Open(myApp),
+		book:  mistakes.Open(myApp),
+		mode:  mode,
+		sess:  sess,
 	}
 	copy(p.items, targets)
-	p.shuffle()
 	return p
 }
 
-func (p *KanaPool) shuffle() {
-	rand.Shuffle(len(p.items), func(i, j int) {
-		p.items[i], p.items[j] = p.items[j], p.items[i]
+// next 到期的假名优先，其次是没练过的，同一假名不会连续出现；
+// 每个假名各练一次时只从还没练过的假名中选
+func (p *KanaPool) next() string {
+	k := p.sched.Next(p.sess.Pending(p.items), p.last)
+	p.last = k
+	p.judged = false
+	p.shownAt = time.Now()
+	return k
+}
+
+// answer 记录判题结果：写入答题记录和本次练习，并更新复习进度（答错的假名会很快再次出现）；
+// 同一题只记录第一次判题，再次判题时 first 为 false。
+// 模式一还会更新错题本，cleared 表示这道题连续答对，已经移出错题本
+func (p *KanaPool) answer(kana, given string, correct bool, m session.Mistake) (first, cleared bool) {
+	if p.judged {
+		return false, false
+	}
+	p.judged = true
+	p.sess.Add(kana, correct, m)
+	p.log.Add(history.Record{
+		Module:  history.ModuleKana,
+		Mode:    p.mode,
+		Item:    kana,
+		Group:   kanaRow(kana),
+		Answer:  given,
+		Correct: correct,
+		Latency: time.Since(p.shownAt),
 	})
-	p.index = 0
+	if err := p.sched.ReviewResult(kana, correct); err != nil {
+		fyne.LogError("保存复习进度失败", err)
+	}
+	if p.mode != modeOneName {
+		return true, false
+	}
+	return true, p.noteMistake(kana, given, correct)
 }
 
-func (p *KanaPool) next() string {
-	if p.index >= len(p.items) {
-		p.shuffle()
+// 五十音模块共用的复习调度器，首次使用时从应用存储中读取
+var kanaSRS *srs.Scheduler
+
+func kanaScheduler(myApp fyne.App) *srs.Scheduler {
+	if kanaSRS == nil {
+		s, err := srs.Load(myApp, "kana")
+		if err != nil {
+			fyne.LogError("读取复习进度失败", err)
+		}
+		kanaSRS = s
 	}
-	k := p.items[p.index]
-	p.index++
-	return k
+	return kanaSRS
 }
 
 // ======================= 模式1： 假名 => 罗马音 =======================
-func showModeOne(myApp fyne.App, targets []string, mainWin fyne.Window, stats *Stats, statsLabel *widget.Label, hSelected, kSelected bool) {
-	w := myApp.NewWindow("模式一")
+// practice 为 true 时是错题本中的"只练错题"，答对的次数计入错题本
+func showModeOne(myApp fyne.App, targets []string, mainWin fyne.Window, stats *Stats, statsLabel *widget.Label, hSelected, kSelected bool, length session.Length, practice bool) {
+	title := "模式一"
+	if practice {
+		title = "模式一 - 只练错题"
+	}
+	w := myApp.NewWindow(title)
+	progress := widget.NewLabel("")
 	question := widget.NewLabel("")
 	answerEntry := widget.NewEntry()
 	feedback := widget.NewLabel("")
 
-	pool := newKanaPool(targets)
+	sess := session.New(length, len(targets))
+	pool := newKanaPool(myApp, modeOneName, targets, sess)
+	pool.practice = practice
 	var currentKana string
 
 	nextQuestion := func() {
+		if sess.Finished() {
+			session.ShowSummary(w, sess, func(missed []string) {
+				w.Close()
+				showModeOne(myApp, missed, mainWin, stats, statsLabel, hSelected, kSelected, session.EachOnce, practice)
+			})
+			return
+		}
+		progress.SetText(sess.Progress())
 		answerEntry.SetText("")
 		feedback.SetText("")
 		currentKana = pool.next()
@@ -552,10 +937,19 @@ func showModeOne(myApp fyne.App, targets []string, mainWin fyne.Window, stats *S
 	judgeBtn := widget.NewButton("判断", func() {
 		q := currentKana
 		ans := strings.TrimSpace(answerEntry.Text)
-		stats.Total++
-		if checkRomaji(q, ans) {
+		correct := checkRomaji(q, ans)
+		first, cleared := pool.answer(q, ans, correct, session.Mistake{Question: q, Answer: ans, Expected: strings.Join(kanaToRomaji[q], "/")})
+		if first {
+			stats.Total++
+			if correct {
+				stats.Correct++
+			}
+		}
+		if correct {
 			feedback.SetText("正确")
-			stats.Correct++
+			if cleared {
+				feedback.SetText(fmt.Sprintf("正确，已连续答对 %d 次，移出错题本", mistakes.ClearAfter(myApp)))
+			}
 		} else {
 			feedback.SetText("错误，正确答案: " + strings.Join(kanaToRomaji[q], "/"))
 		}
@@ -571,6 +965,7 @@ func showModeOne(myApp fyne.App, targets []string, mainWin fyne.Window, stats *S
 	})
 
 	w.SetContent(container.NewVBox(
+		progress,
 		question,
 		answerEntry,
 		container.NewHBox(judgeBtn, nextBtn),
@@ -583,21 +978,34 @@ func showModeOne(myApp fyne.App, targets []string, mainWin fyne.Window, stats *S
 }
 
 // ======================= 模式2： 罗马音 => 假名手写 =======================
-func showModeTwo(myApp fyne.App, targets []string, mainWin fyne.Window, hSelected, kSelected bool) {
+func showModeTwo(myApp fyne.App, targets []string, mainWin fyne.Window, stats *Stats, statsLabel *widget.Label, hSelected, kSelected bool, length session.Length) {
 	w := myApp.NewWindow("模式二")
 
+	progress := widget.NewLabel("")
 	question := widget.NewLabel("")
-	feedback := widget.NewLabel("用鼠标在下方空白处写出假名后，点击显示答案以进行比较。正确答案: ")
+	feedback := widget.NewLabel("用鼠标在下方空白处写出假名后，点击判题自动识别，或点击显示答案以进行比较。正确答案: ")
 	drawingArea := newDrawingWidget()
+	animator := newStrokeAnimator(drawingArea)
 	clearBtn := widget.NewButtonWithIcon("清空画布", theme.ContentClearIcon(), func() {
+		animator.Stop()
 		drawingArea.Clear()
 	})
 
-	pool := newKanaPool(targets)
+	sess := session.New(length, len(targets))
+	pool := newKanaPool(myApp, modeTwoName, targets, sess)
 	var currentRomaji string
 	var currentKana string
 
 	nextQuestion := func() {
+		animator.Stop()
+		if sess.Finished() {
+			session.ShowSummary(w, sess, func(missed []string) {
+				w.Close()
+				showModeTwo(myApp, missed, mainWin, stats, statsLabel, hSelected, kSelected, session.EachOnce)
+			})
+			return
+		}
+		progress.SetText(sess.Progress())
 		drawingArea.Clear()
 		feedback.SetText("正确答案: ")
 		currentKana = pool.next()
@@ -616,8 +1024,49 @@ func showModeTwo(myApp fyne.App, targets []string, mainWin fyne.Window, hSelecte
 		}
 	}
 
+	judgeBtn := widget.NewButton("判题", func() {
+		strokes := drawingArea.Strokes()
+		if len(strokes) == 0 {
+			feedback.SetText("请先在下方空白处写出假名")
+			return
+		}
+		results := recognizeKana(strokes, recognitionCandidates(currentKana))
+		if len(results) == 0 {
+			feedback.SetText("无法识别，正确答案: " + currentKana)
+			return
+		}
+
+		var top []string
+		for i := 0; i < len(results) && i < 3; i++ {
+			top = append(top, fmt.Sprintf("%s %.0f%%", results[i].kana, results[i].confidence*100))
+		}
+		msg := ""
+		correct := results[0].kana == currentKana
+		if first, _ := pool.answer(currentKana, results[0].kana, correct, session.Mistake{
+			Question: question.Text,
+			Answer:   results[0].kana,
+			Expected: currentKana,
+		}); first {
+			stats.Total++
+			if correct {
+				stats.Correct++
+			}
+		}
+		if correct {
+			msg = "正确！识别结果: " + strings.Join(top, " / ")
+		} else {
+			msg = "错误，正确答案: " + currentKana + "，识别结果: " + strings.Join(top, " / ")
+		}
+		if problems := checkStrokeOrder(drawingArea.DrawnStrokes(), currentKana); len(problems) > 0 {
+			msg += "\n" + strings.Join(problems, "\n")
+		}
+		feedback.SetText(msg)
+		statsLabel.SetText(fmt.Sprintf("当前正确率: %.2f%%", stats.Accuracy()))
+	})
+
 	showAnswerBtn := widget.NewButton("显示答案", func() {
 		feedback.SetText("正确答案: " + currentKana)
+		animator.Play(currentKana)
 	})
 
 	nextBtn := widget.NewButton("下一题", func() {
@@ -629,8 +1078,8 @@ func showModeTwo(myApp fyne.App, targets []string, mainWin fyne.Window, hSelecte
 	})
 
 	w.SetContent(container.NewBorder(
-		container.NewVBox(question, container.NewHBox(showAnswerBtn, nextBtn), feedback),
-		container.NewHBox(backBtn, clearBtn),
+		container.NewVBox(progress, question, container.NewHBox(judgeBtn, showAnswerBtn, nextBtn), feedback),
+		container.NewVBox(animator.Controls(), container.NewHBox(backBtn, clearBtn)),
 		nil, nil,
 		drawingArea,
 	))
@@ -640,12 +1089,25 @@ func showModeTwo(myApp fyne.App, targets []string, mainWin fyne.Window, hSelecte
 }
 
 // ======================= 自定义绘图widget =======================
+// drawnStroke 是一次按下到抬起之间的笔迹，times[i] 为 points[i] 的记录时间
+type drawnStroke struct {
+	points []fyne.Position
+	times  []time.Time
+}
+
 type drawingWidget struct {
 	widget.BaseWidget
 	lines    []*canvas.Line
+	strokes  []drawnStroke
 	lastPos  *fyne.Position
 	bg       *canvas.Rectangle
 	renderer *drawingRenderer
+
+	// 笔顺演示：guide 为标准笔画，前 guideDone 笔完整显示，第 guideDone 笔显示 guideProgress
+	guide         []stroke
+	guideDone     int
+	guideProgress float32
+	guideLines    []fyne.CanvasObject
 }
 
 func newDrawingWidget() *drawingWidget {
@@ -667,6 +1129,10 @@ func (d *drawingWidget) CreateRenderer() fyne.WidgetRenderer {
 func (d *drawingWidget) Dragged(event *fyne.DragEvent) {
 	if d.lastPos == nil {
 		d.lastPos = &event.Position
+		d.strokes = append(d.strokes, drawnStroke{
+			points: []fyne.Position{event.Position},
+			times:  []time.Time{time.Now()},
+		})
 		return
 	}
 	line := canvas.NewLine(color.Black)
@@ -675,6 +1141,9 @@ func (d *drawingWidget) Dragged(event *fyne.DragEvent) {
 	line.Position2 = event.Position
 	d.lines = append(d.lines, line)
 	d.renderer.objs = append(d.renderer.objs, line)
+	curr := &d.strokes[len(d.strokes)-1]
+	curr.points = append(curr.points, event.Position)
+	curr.times = append(curr.times, time.Now())
 	canvas.Refresh(d)
 	d.lastPos = &event.Position
 }
@@ -689,10 +1158,30 @@ func (d *drawingWidget) Tapped(_ *fyne.PointEvent) {
 
 func (d *drawingWidget) Clear() {
 	d.lines = nil
+	d.strokes = nil
+	d.guide = nil
+	d.guideLines = nil
 	d.renderer.objs = []fyne.CanvasObject{d.bg}
 	canvas.Refresh(d)
 }
 
+// Strokes 返回当前画布上的所有笔画，每次按下到抬起为一笔
+func (d *drawingWidget) Strokes() []stroke {
+	res := make([]stroke, len(d.strokes))
+	for i, ds := range d.strokes {
+		res[i] = make(stroke, len(ds.points))
+		copy(res[i], ds.points)
+	}
+	return res
+}
+
+// DrawnStrokes 返回带时间戳的笔画，按书写先后排列
+func (d *drawingWidget) DrawnStrokes() []drawnStroke {
+	res := make([]drawnStroke, len(d.strokes))
+	copy(res, d.strokes)
+	return res
+}
+
 type drawingRenderer struct {
 	d    *drawingWidget
 	objs []fyne.CanvasObject
@@ -700,6 +1189,9 @@ type drawingRenderer struct {
 
 func (r *drawingRenderer) Layout(size fyne.Size) {
 	r.objs[0].Resize(size)
+	if len(r.d.guide) > 0 {
+		r.d.layoutGuide(size)
+	}
 }
 
 func (r *drawingRenderer) MinSize() fyne.Size {
@@ -743,6 +1235,30 @@ func reverseFindKana(romaji string) string {
 	return "null"
 }
 
+// 识别时的候选集合：与目标同一种假名（平假名或片假名）的全部假名
+func recognitionCandidates(kana string) []string {
+	hira := isHiragana(kana)
+	var res []string
+	for _, line := range gojuon {
+		if hira {
+			res = append(res, line.hiragana...)
+		} else {
+			res = append(res, line.katakana...)
+		}
+	}
+	return res
+}
+
+// kanaRow 返回假名所在的五十音行，如 "ka行"
+func kanaRow(kana string) string {
+	for _, line := range gojuon {
+		if contains(line.hiragana, kana) || contains(line.katakana, kana) {
+			return line.label()
+		}
+	}
+	return ""
+}
+
 func isHiragana(c string) bool {
 	for _, line := range gojuon {
 		for _, h := range line.hiragana {
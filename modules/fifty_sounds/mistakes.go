@@ -0,0 +1,49 @@
+package fifty_sounds
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/history"
+	"FiftySound/modules/mistakes"
+	"FiftySound/modules/session"
+)
+
+// ======================= 错题本 =======================
+// 模式一答错的假名记入错题本；在错题本中"只练错题"时，
+// 连续答对指定次数后移出错题本
+
+// noteMistake 更新错题本，由 answer 在每道题第一次判题时调用
+func (p *KanaPool) noteMistake(kana, given string, correct bool) (cleared bool) {
+	if !correct {
+		p.book.Miss(mistakes.Entry{
+			Module:   history.ModuleKana,
+			Mode:     p.mode,
+			Item:     kana,
+			Group:    kanaRow(kana),
+			Question: kana,
+			Answer:   given,
+			Expected: strings.Join(kanaToRomaji[kana], "/"),
+		})
+		return false
+	}
+	return p.practice && p.book.Hit(history.ModuleKana, p.mode, kana)
+}
+
+// PracticeMistakes 用模式一练习错题本中的假名
+func PracticeMistakes(myApp fyne.App, parent fyne.Window, entries []mistakes.Entry) {
+	var targets []string
+	for _, e := range entries {
+		if _, ok := kanaToRomaji[e.Item]; ok && !contains(targets, e.Item) {
+			targets = append(targets, e.Item)
+		}
+	}
+	if len(targets) == 0 {
+		dialog.ShowInformation("提示", "没有可以练习的假名错题", parent)
+		return
+	}
+	showModeOne(myApp, targets, parent, &Stats{}, widget.NewLabel(""), true, true, session.Load(myApp), true)
+}
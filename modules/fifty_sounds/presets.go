@@ -0,0 +1,303 @@
+package fifty_sounds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ======================= 练习预设 =======================
+// 预设保存所选假名、平假名/片假名选项和练习模式，保存在 Preferences 中；
+// 另外记住上次的选择，重新打开程序后自动恢复
+
+const (
+	presetsPrefKey   = "fifty_sounds.presets"
+	selectionPrefKey = "fifty_sounds.selection"
+)
+
+// Preset 一个练习预设
+type Preset struct {
+	Name     string   `json:"name"`
+	Chars    []string `json:"chars"`
+	Hiragana bool     `json:"hiragana"`
+	Katakana bool     `json:"katakana"`
+	Mode     string   `json:"mode,omitempty"`
+}
+
+// builtinPresets 内置预设，不能重命名和删除
+var builtinPresets = []Preset{
+	{
+		Name:     "形近字 (シツソン)",
+		Chars:    []string{"シ", "ツ", "ソ", "ン"},
+		Katakana: true,
+		Mode:     modeOneName,
+	},
+	{
+		Name:     "只练浊音",
+		Chars:    lineChars(func(l gojuonLine) bool { return contains([]string{"ga", "za", "da", "ba", "pa"}, l.romaji) }),
+		Hiragana: true,
+		Katakana: true,
+		Mode:     modeOneName,
+	},
+	{
+		Name: "只练拗音",
+		Chars: lineChars(func(l gojuonLine) bool {
+			return !l.extended && len(l.hiragana) > 0 && len([]rune(l.hiragana[0])) == 2
+		}),
+		Hiragana: true,
+		Katakana: true,
+		Mode:     modeOneName,
+	},
+}
+
+// lineChars 满足 match 的行中的全部假名（平假名和片假名）
+func lineChars(match func(gojuonLine) bool) []string {
+	var res []string
+	for _, line := range gojuon {
+		if match(line) {
+			res = append(res, line.hiragana...)
+			res = append(res, line.katakana...)
+		}
+	}
+	return res
+}
+
+func isBuiltinPreset(name string) bool {
+	for _, p := range builtinPresets {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPresets 读取用户保存的预设（不含内置预设）
+func loadPresets(myApp fyne.App) []Preset {
+	raw := myApp.Preferences().String(presetsPrefKey)
+	if raw == "" {
+		return nil
+	}
+	var presets []Preset
+	if err := json.Unmarshal([]byte(raw), &presets); err != nil {
+		fyne.LogError("读取练习预设失败", err)
+		return nil
+	}
+	return presets
+}
+
+func savePresets(myApp fyne.App, presets []Preset) error {
+	if presets == nil {
+		presets = []Preset{}
+	}
+	data, err := json.Marshal(presets)
+	if err != nil {
+		return err
+	}
+	myApp.Preferences().SetString(presetsPrefKey, string(data))
+	return nil
+}
+
+// loadSelection 读取上次的选择，从未保存过时 ok 为 false
+func loadSelection(myApp fyne.App) (p Preset, ok bool) {
+	raw := myApp.Preferences().String(selectionPrefKey)
+	if raw == "" {
+		return Preset{}, false
+	}
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		fyne.LogError("读取上次的假名选择失败", err)
+		return Preset{}, false
+	}
+	return p, true
+}
+
+func saveSelection(myApp fyne.App, p Preset) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		fyne.LogError("保存假名选择失败", err)
+		return
+	}
+	myApp.Preferences().SetString(selectionPrefKey, string(data))
+}
+
+// validatePresetName 检查预设名，names 为其它预设的名字，不能重复
+func validatePresetName(name string, names []string) error {
+	switch {
+	case name == "":
+		return errors.New("请填写预设名称")
+	case isBuiltinPreset(name) || contains(names, name):
+		return fmt.Errorf("已经有名为 %s 的预设", name)
+	}
+	return nil
+}
+
+// presetBar 五十音窗口中的预设选择栏：选中即应用，可以把当前选择保存为预设，
+// 以及重命名、删除自己保存的预设
+type presetBar struct {
+	myApp   fyne.App
+	win     fyne.Window
+	user    []Preset
+	sel     *widget.Select
+	current func() Preset // 当前的选择
+	apply   func(Preset)  // 应用预设
+}
+
+func newPresetBar(myApp fyne.App, win fyne.Window, current func() Preset, apply func(Preset)) *presetBar {
+	b := &presetBar{
+		myApp:   myApp,
+		win:     win,
+		user:    loadPresets(myApp),
+		current: current,
+		apply:   apply,
+	}
+	b.sel = widget.NewSelect(nil, func(name string) {
+		if p, ok := b.find(name); ok {
+			b.apply(p)
+		}
+	})
+	b.sel.PlaceHolder = "选择练习预设"
+	b.refresh()
+	return b
+}
+
+func (b *presetBar) all() []Preset {
+	return append(append([]Preset{}, builtinPresets...), b.user...)
+}
+
+func (b *presetBar) find(name string) (Preset, bool) {
+	for _, p := range b.all() {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Preset{}, false
+}
+
+// userIndex 用户预设的下标，内置预设或不存在时为 -1
+func (b *presetBar) userIndex(name string) int {
+	for i, p := range b.user {
+		if p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// userNames 除了 skip 以外的用户预设名
+func (b *presetBar) userNames(skip int) []string {
+	var res []string
+	for i, p := range b.user {
+		if i != skip {
+			res = append(res, p.Name)
+		}
+	}
+	return res
+}
+
+func (b *presetBar) refresh() {
+	var names []string
+	for _, p := range b.all() {
+		names = append(names, p.Name)
+	}
+	b.sel.Options = names
+	b.sel.Refresh()
+}
+
+// setSelected 修改下拉框显示的预设，不再次应用
+func (b *presetBar) setSelected(name string) {
+	f := b.sel.OnChanged
+	b.sel.OnChanged = nil
+	if name == "" {
+		b.sel.ClearSelected()
+	} else {
+		b.sel.SetSelected(name)
+	}
+	b.sel.OnChanged = f
+}
+
+func (b *presetBar) save() {
+	if err := savePresets(b.myApp, b.user); err != nil {
+		dialog.ShowError(err, b.win)
+	}
+}
+
+// askName 弹出输入预设名的对话框
+func (b *presetBar) askName(title, name string, skip int, onDone func(string)) {
+	entry := widget.NewEntry()
+	entry.SetText(name)
+	items := []*widget.FormItem{widget.NewFormItem("名称", entry)}
+	dlg := dialog.NewForm(title, "确定", "取消", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		name := strings.TrimSpace(entry.Text)
+		if err := validatePresetName(name, b.userNames(skip)); err != nil {
+			dialog.ShowError(err, b.win)
+			return
+		}
+		onDone(name)
+	}, b.win)
+	dlg.Resize(fyne.NewSize(360, 160))
+	dlg.Show()
+}
+
+// selectedUser 当前选中的用户预设，没有选中或选中的是内置预设时提示并返回 -1
+func (b *presetBar) selectedUser() int {
+	name := b.sel.Selected
+	if name == "" {
+		dialog.ShowInformation("提示", "请先选择一个预设", b.win)
+		return -1
+	}
+	i := b.userIndex(name)
+	if i < 0 {
+		dialog.ShowInformation("提示", "内置预设不能修改", b.win)
+	}
+	return i
+}
+
+func (b *presetBar) content() fyne.CanvasObject {
+	saveBtn := widget.NewButton("保存为预设", func() {
+		b.askName("保存为预设", "", -1, func(name string) {
+			p := b.current()
+			p.Name = name
+			b.user = append(b.user, p)
+			b.save()
+			b.refresh()
+			b.setSelected(name)
+		})
+	})
+	renameBtn := widget.NewButton("重命名", func() {
+		i := b.selectedUser()
+		if i < 0 {
+			return
+		}
+		b.askName("重命名预设", b.user[i].Name, i, func(name string) {
+			b.user[i].Name = name
+			b.save()
+			b.refresh()
+			b.setSelected(name)
+		})
+	})
+	removeBtn := widget.NewButton("删除", func() {
+		i := b.selectedUser()
+		if i < 0 {
+			return
+		}
+		dialog.ShowConfirm("删除预设", fmt.Sprintf("确定删除预设 %s 吗？", b.user[i].Name), func(ok bool) {
+			if !ok {
+				return
+			}
+			b.user = append(b.user[:i:i], b.user[i+1:]...)
+			b.save()
+			b.refresh()
+			b.setSelected("")
+		}, b.win)
+	})
+	return container.NewBorder(nil, nil, widget.NewLabel("预设："),
+		container.NewHBox(saveBtn, renameBtn, removeBtn), b.sel)
+}
@@ -0,0 +1,246 @@
+package fifty_sounds
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/history"
+	"FiftySound/modules/romaji"
+	"FiftySound/modules/session"
+	"FiftySound/modules/srs"
+	"FiftySound/modules/vocabulary"
+)
+
+// ======================= 模式三： 单词 => 罗马音 =======================
+// 从词库中挑出只由所选假名组成的单词，输入整个单词的罗马音，
+// 平文式、训令式、日本式的写法都算对
+
+// readingGroup 单词朗读在答题记录中的分组
+const readingGroup = "单词"
+
+// readingWords 从 words 中挑出假名只用到 targets 的单词，按假名分组（同音的单词放在一起）
+func readingWords(words []vocabulary.WordItem, targets []string) map[string][]vocabulary.WordItem {
+	allowed := make(map[string]bool)
+	for _, t := range targets {
+		allowed[t] = true
+	}
+	res := make(map[string][]vocabulary.WordItem)
+	for _, w := range words {
+		kana := strings.TrimSpace(w.Kana)
+		if usesOnly(kana, allowed) {
+			res[kana] = append(res[kana], w)
+		}
+	}
+	return res
+}
+
+// usesOnly 判断 kana 是否只由 allowed 中的假名组成；拗音按整体判断，
+// 促音 っ 和长音 ー 不单独选择，跟在假名后面时总是允许
+func usesOnly(kana string, allowed map[string]bool) bool {
+	rs := []rune(kana)
+	if len(rs) == 0 {
+		return false
+	}
+	for i := 0; i < len(rs); i++ {
+		if i+1 < len(rs) && strings.ContainsRune("ゃゅょャュョ", rs[i+1]) {
+			if !allowed[string(rs[i:i+2])] {
+				return false
+			}
+			i++
+			continue
+		}
+		switch {
+		case allowed[string(rs[i])]:
+		case i > 0 && strings.ContainsRune("っッー", rs[i]):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// readingPool 按间隔重复调度单词，复习进度与单个假名分开保存
+type readingPool struct {
+	words   map[string][]vocabulary.WordItem
+	keys    []string
+	sched   *srs.Scheduler
+	log     *history.Log
+	sess    *session.Session
+	judged  bool // 当前题目是否已经判过
+	last    string
+	shownAt time.Time
+}
+
+func newReadingPool(myApp fyne.App, words map[string][]vocabulary.WordItem, length session.Length) *readingPool {
+	p := &readingPool{
+		words: words,
+		sched: readingScheduler(myApp),
+		log:   history.Open(myApp),
+		sess:  session.New(length, len(words)),
+	}
+	for k := range words {
+		p.keys = append(p.keys, k)
+	}
+	return p
+}
+
+func (p *readingPool) next() string {
+	k := p.sched.Next(p.sess.Pending(p.keys), p.last)
+	p.last = k
+	p.judged = false
+	p.shownAt = time.Now()
+	return k
+}
+
+// answer 记录第一次判题的结果，再次判题时 first 为 false
+func (p *readingPool) answer(kana, given string, correct bool) (first bool) {
+	if p.judged {
+		return false
+	}
+	p.judged = true
+	p.sess.Add(kana, correct, session.Mistake{
+		Question: kana,
+		Answer:   given,
+		Expected: readingAnswers(kana),
+	})
+	p.log.Add(history.Record{
+		Module:  history.ModuleKana,
+		Mode:    modeThreeName,
+		Item:    kana,
+		Group:   readingGroup,
+		Answer:  given,
+		Correct: correct,
+		Latency: time.Since(p.shownAt),
+	})
+	if err := p.sched.ReviewResult(kana, correct); err != nil {
+		fyne.LogError("保存复习进度失败", err)
+	}
+	return true
+}
+
+var readingSRS *srs.Scheduler
+
+func readingScheduler(myApp fyne.App) *srs.Scheduler {
+	if readingSRS == nil {
+		s, err := srs.Load(myApp, "reading")
+		if err != nil {
+			fyne.LogError("读取复习进度失败", err)
+		}
+		readingSRS = s
+	}
+	return readingSRS
+}
+
+// readingAnswers 各种拼写方式下的罗马音，去掉重复的写法，如 "chotto / tyotto"
+func readingAnswers(kana string) string {
+	var forms []string
+	for _, sys := range romaji.Systems {
+		if f := romaji.ToRomaji(kana, sys); !contains(forms, f) {
+			forms = append(forms, f)
+		}
+	}
+	return strings.Join(forms, " / ")
+}
+
+// meanings 同音单词的汉字和中文释义，如 "学校: 学校"
+func meanings(words []vocabulary.WordItem) string {
+	var lines []string
+	for _, w := range words {
+		line := strings.Join(w.Chines, "/")
+		if w.Kanji != "" {
+			line = w.Kanji + ": " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func showModeThree(myApp fyne.App, targets []string, mainWin fyne.Window, stats *Stats, statsLabel *widget.Label, length session.Length) {
+	vocabulary.LibraryWords(myApp, mainWin, func(all []vocabulary.WordItem) {
+		words := readingWords(all, targets)
+		if len(words) == 0 {
+			dialog.ShowInformation("提示", "词库中没有只由所选假名组成的单词，请多选择一些假名。", mainWin)
+			return
+		}
+		showReadingWindow(myApp, newReadingPool(myApp, words, length), stats, statsLabel)
+	})
+}
+
+func showReadingWindow(myApp fyne.App, pool *readingPool, stats *Stats, statsLabel *widget.Label) {
+	w := myApp.NewWindow("模式三")
+	progress := widget.NewLabel("")
+	question := widget.NewLabel("")
+	answerEntry := widget.NewEntry()
+	feedback := widget.NewLabel("")
+	countLabel := widget.NewLabel(fmt.Sprintf("共 %d 个可练习的单词", len(pool.keys)))
+
+	var currentKana string
+
+	nextQuestion := func() {
+		if pool.sess.Finished() {
+			session.ShowSummary(w, pool.sess, func(missed []string) {
+				words := make(map[string][]vocabulary.WordItem)
+				for _, k := range missed {
+					words[k] = pool.words[k]
+				}
+				w.Close()
+				showReadingWindow(myApp, newReadingPool(myApp, words, session.EachOnce), stats, statsLabel)
+			})
+			return
+		}
+		progress.SetText(pool.sess.Progress())
+		answerEntry.SetText("")
+		feedback.SetText("")
+		currentKana = pool.next()
+		question.SetText(fmt.Sprintf("请输入单词 %s 的罗马音：", currentKana))
+	}
+
+	judgeBtn := widget.NewButton("判断", func() {
+		ans := strings.TrimSpace(answerEntry.Text)
+		correct := romaji.Match(ans, currentKana)
+		msg := ""
+		if correct {
+			msg = "正确: " + readingAnswers(currentKana)
+		} else {
+			msg = "错误，正确答案: " + readingAnswers(currentKana)
+		}
+		feedback.SetText(msg + "\n" + meanings(pool.words[currentKana]))
+		if pool.answer(currentKana, ans, correct) {
+			stats.Total++
+			if correct {
+				stats.Correct++
+			}
+		}
+		statsLabel.SetText(fmt.Sprintf("当前正确率: %.2f%%", stats.Accuracy()))
+	})
+	answerEntry.OnSubmitted = func(string) {
+		judgeBtn.OnTapped()
+	}
+
+	nextBtn := widget.NewButton("下一题", func() {
+		nextQuestion()
+	})
+
+	backBtn := widget.NewButton("返回主界面", func() {
+		w.Close()
+	})
+
+	w.SetContent(container.NewVBox(
+		countLabel,
+		progress,
+		question,
+		answerEntry,
+		container.NewHBox(judgeBtn, nextBtn),
+		feedback,
+		backBtn,
+	))
+	w.Resize(fyne.NewSize(400, 300))
+	nextQuestion()
+	w.Show()
+}
@@ -0,0 +1,180 @@
+package fifty_sounds
+
+import (
+	"math"
+	"sort"
+
+	"fyne.io/fyne/v2"
+)
+
+// ======================= 手写识别 (模板匹配 + DTW) =======================
+
+const (
+	resamplePoints     = 16   // 每一笔重采样后的点数
+	strokeCountPenalty = 0.08 // 笔画数每相差一笔增加的距离
+	confidenceScale    = 0.02 // 距离 => 置信度时的温度
+)
+
+type candidate struct {
+	kana       string
+	distance   float64
+	confidence float64
+}
+
+// recognizeKana 把手写笔画与 candidates 中每个假名的模板比较，按相似度从高到低返回
+func recognizeKana(strokes []stroke, candidates []string) []candidate {
+	input := normalizeStrokes(strokes)
+	if len(input) == 0 {
+		return nil
+	}
+	inputSeq := flattenStrokes(input)
+
+	var res []candidate
+	for _, kana := range candidates {
+		tpl := kanaStrokes(kana)
+		if len(tpl) == 0 {
+			continue
+		}
+		tplSeq := flattenStrokes(normalizeStrokes(tpl))
+		d := dtwDistance(inputSeq, tplSeq)
+		d += strokeCountPenalty * math.Abs(float64(len(input)-len(tpl)))
+		res = append(res, candidate{kana: kana, distance: d})
+	}
+	if len(res) == 0 {
+		return nil
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].distance < res[j].distance
+	})
+
+	// 以最优距离为基准做 softmax，避免 exp 下溢
+	best := res[0].distance
+	sum := 0.0
+	for i := range res {
+		res[i].confidence = math.Exp(-(res[i].distance - best) / confidenceScale)
+		sum += res[i].confidence
+	}
+	for i := range res {
+		res[i].confidence /= sum
+	}
+	return res
+}
+
+// normalizeStrokes 把整体缩放到单位方格中并居中（保持宽高比），每一笔重采样为等距点
+func normalizeStrokes(strokes []stroke) []stroke {
+	minX, minY := float32(math.MaxFloat32), float32(math.MaxFloat32)
+	maxX, maxY := float32(-math.MaxFloat32), float32(-math.MaxFloat32)
+	count := 0
+	for _, st := range strokes {
+		for _, p := range st {
+			minX = min(minX, p.X)
+			minY = min(minY, p.Y)
+			maxX = max(maxX, p.X)
+			maxY = max(maxY, p.Y)
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+
+	size := max(maxX-minX, maxY-minY)
+	if size == 0 {
+		size = 1
+	}
+	offX := (size - (maxX - minX)) / 2
+	offY := (size - (maxY - minY)) / 2
+
+	var res []stroke
+	for _, st := range strokes {
+		if len(st) == 0 {
+			continue
+		}
+		scaled := make(stroke, len(st))
+		for i, p := range st {
+			scaled[i] = fyne.NewPos((p.X-minX+offX)/size, (p.Y-minY+offY)/size)
+		}
+		res = append(res, resampleStroke(scaled, resamplePoints))
+	}
+	return res
+}
+
+// resampleStroke 沿笔画路径取 n 个等间距的点
+func resampleStroke(st stroke, n int) stroke {
+	if len(st) == 1 {
+		res := make(stroke, n)
+		for i := range res {
+			res[i] = st[0]
+		}
+		return res
+	}
+
+	total := float32(0)
+	for i := 1; i < len(st); i++ {
+		total += distance(st[i-1], st[i])
+	}
+	if total == 0 {
+		return resampleStroke(st[:1], n)
+	}
+
+	step := total / float32(n-1)
+	res := stroke{st[0]}
+	acc := float32(0)
+	prev := st[0]
+	for i := 1; i < len(st) && len(res) < n; {
+		d := distance(prev, st[i])
+		if acc+d >= step && d > 0 {
+			t := (step - acc) / d
+			p := fyne.NewPos(prev.X+t*(st[i].X-prev.X), prev.Y+t*(st[i].Y-prev.Y))
+			res = append(res, p)
+			prev = p
+			acc = 0
+			continue
+		}
+		acc += d
+		prev = st[i]
+		i++
+	}
+	for len(res) < n {
+		res = append(res, st[len(st)-1])
+	}
+	return res
+}
+
+func flattenStrokes(strokes []stroke) []fyne.Position {
+	var seq []fyne.Position
+	for _, st := range strokes {
+		seq = append(seq, st...)
+	}
+	return seq
+}
+
+// dtwDistance 动态时间规整距离，按路径长度归一化
+func dtwDistance(a, b []fyne.Position) float64 {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return math.Inf(1)
+	}
+	inf := math.Inf(1)
+	prev := make([]float64, m+1)
+	curr := make([]float64, m+1)
+	for j := range prev {
+		prev[j] = inf
+	}
+	prev[0] = 0
+	for i := 1; i <= n; i++ {
+		curr[0] = inf
+		for j := 1; j <= m; j++ {
+			cost := float64(distance(a[i-1], b[j-1]))
+			curr[j] = cost + math.Min(prev[j-1], math.Min(prev[j], curr[j-1]))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[m] / float64(n+m)
+}
+
+func distance(p, q fyne.Position) float32 {
+	dx, dy := p.X-q.X, p.Y-q.Y
+	return float32(math.Sqrt(float64(dx*dx + dy*dy)))
+}
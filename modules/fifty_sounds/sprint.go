@@ -0,0 +1,247 @@
+package fifty_sounds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/session"
+)
+
+// ======================= 模式七： 限时冲刺 =======================
+// 在限定时间内尽量多地输入假名的罗马音：回车自动判题并进入下一题，
+// 每题的用时写入答题记录。时间到或者做完设定的题量后结束，
+// 报告每分钟答题数、正确率和最慢的假名，并可以查看本次练习的总结、只练错题
+
+// sprintDurations 可选的冲刺时长
+var sprintDurations = []time.Duration{60 * time.Second, 120 * time.Second, 300 * time.Second}
+
+// slowestCount 报告中列出的最慢假名个数
+const slowestCount = 5
+
+// sprintResult 冲刺中的一题
+type sprintResult struct {
+	kana    string
+	correct bool
+	latency time.Duration
+}
+
+// sprintReport 冲刺结束后的报告，d 为实际用时
+func sprintReport(results []sprintResult, d time.Duration) string {
+	if len(results) == 0 {
+		return "这次没有答题。"
+	}
+	correct := 0
+	total := make(map[string]time.Duration)
+	count := make(map[string]int)
+	for _, r := range results {
+		if r.correct {
+			correct++
+		}
+		total[r.kana] += r.latency
+		count[r.kana]++
+	}
+	minutes := d.Minutes()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "共答 %d 题，答对 %d 题\n", len(results), correct)
+	fmt.Fprintf(&b, "速度: 每分钟 %.1f 个假名（答对的每分钟 %.1f 个）\n",
+		float64(len(results))/minutes, float64(correct)/minutes)
+	fmt.Fprintf(&b, "正确率: %.2f%%\n", float64(correct)/float64(len(results))*100)
+
+	// 按平均用时从慢到快
+	kana := make([]string, 0, len(total))
+	for k := range total {
+		kana = append(kana, k)
+	}
+	avg := func(k string) time.Duration { return total[k] / time.Duration(count[k]) }
+	sort.Slice(kana, func(i, j int) bool { return avg(kana[i]) > avg(kana[j]) })
+	if len(kana) > slowestCount {
+		kana = kana[:slowestCount]
+	}
+	b.WriteString("最慢的假名:")
+	for _, k := range kana {
+		fmt.Fprintf(&b, "\n%s (%s): 平均 %.1f 秒", k, strings.Join(kanaToRomaji[k], "/"), avg(k).Seconds())
+	}
+	return b.String()
+}
+
+func showModeSeven(myApp fyne.App, targets []string, mainWin fyne.Window, stats *Stats, statsLabel *widget.Label, length session.Length) {
+	w := myApp.NewWindow("模式七")
+
+	var options []string
+	for _, d := range sprintDurations {
+		options = append(options, fmt.Sprintf("%d 秒", int(d.Seconds())))
+	}
+	durationRadio := widget.NewRadioGroup(options, nil)
+	durationRadio.Horizontal = true
+	durationRadio.SetSelected(options[0])
+
+	backBtn := widget.NewButton("返回主界面", func() {
+		w.Close()
+	})
+
+	var stop chan struct{}
+	w.SetOnClosed(func() {
+		if stop != nil {
+			close(stop)
+		}
+	})
+
+	var showSetup func()
+	start := func(d time.Duration) {
+		pool := newKanaPool(myApp, modeSevenName, targets, session.New(length, len(targets)))
+		countdown := widget.NewLabel("")
+		progress := widget.NewLabel("")
+		question := widget.NewLabel("")
+		answerEntry := widget.NewEntry()
+		last := widget.NewLabel("输入罗马音后按回车，自动判题并进入下一题")
+		report := widget.NewLabel("")
+
+		var mu sync.Mutex
+		var results []sprintResult
+		finished := false
+		var currentKana string
+
+		// 倒计时；时间到、做完题目、关闭窗口或重新开始时停止
+		if stop != nil {
+			close(stop)
+		}
+		done := make(chan struct{})
+		stop = done
+		begin := time.Now()
+
+		summaryBtn := widget.NewButton("查看总结", func() {
+			session.ShowSummary(w, pool.sess, func(missed []string) {
+				w.Close()
+				showModeSeven(myApp, missed, mainWin, stats, statsLabel, session.EachOnce)
+			})
+		})
+		summaryBtn.Hide()
+
+		// finish 结束本次冲刺，调用时需持有 mu
+		finish := func(reason string) {
+			if finished {
+				return
+			}
+			finished = true
+			if stop == done {
+				close(stop)
+				stop = nil
+			}
+			answerEntry.Disable()
+			question.SetText("")
+			countdown.SetText(reason)
+			report.SetText(sprintReport(results, time.Since(begin)))
+			summaryBtn.Show()
+		}
+
+		nextQuestion := func() {
+			if pool.sess.Finished() {
+				finish("题目做完了")
+				return
+			}
+			progress.SetText(pool.sess.Progress())
+			answerEntry.SetText("")
+			currentKana = pool.next()
+			question.SetText(fmt.Sprintf("%s 的罗马音：", currentKana))
+		}
+
+		answerEntry.OnSubmitted = func(text string) {
+			mu.Lock()
+			defer mu.Unlock()
+			if finished {
+				return
+			}
+			ans := strings.TrimSpace(text)
+			if ans == "" {
+				return
+			}
+			latency := time.Since(pool.shownAt)
+			correct := checkRomaji(currentKana, ans)
+			results = append(results, sprintResult{currentKana, correct, latency})
+			pool.answer(currentKana, ans, correct, session.Mistake{Question: currentKana, Answer: ans, Expected: strings.Join(kanaToRomaji[currentKana], "/")})
+
+			stats.Total++
+			if correct {
+				stats.Correct++
+				last.SetText(fmt.Sprintf("上一题 %s 正确 (%.1f 秒)", currentKana, latency.Seconds()))
+			} else {
+				last.SetText(fmt.Sprintf("上一题 %s 错误，正确答案: %s", currentKana, strings.Join(kanaToRomaji[currentKana], "/")))
+			}
+			statsLabel.SetText(fmt.Sprintf("当前正确率: %.2f%%", stats.Accuracy()))
+			nextQuestion()
+		}
+
+		againBtn := widget.NewButton("再来一次", func() {
+			showSetup()
+		})
+
+		w.SetContent(container.NewVBox(
+			countdown,
+			progress,
+			question,
+			answerEntry,
+			last,
+			report,
+			container.NewHBox(againBtn, summaryBtn, backBtn),
+		))
+		nextQuestion()
+		w.Canvas().Focus(answerEntry)
+
+		end := begin.Add(d)
+		countdown.SetText(fmt.Sprintf("剩余 %d 秒", int(d.Seconds())))
+		go func() {
+			ticker := time.NewTicker(200 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case now := <-ticker.C:
+					left := end.Sub(now)
+					if left <= 0 {
+						fyne.Do(func() {
+							mu.Lock()
+							defer mu.Unlock()
+							finish("时间到")
+						})
+						return
+					}
+					fyne.Do(func() {
+						countdown.SetText(fmt.Sprintf("剩余 %d 秒", int(left.Seconds()+0.999)))
+					})
+				}
+			}
+		}()
+	}
+
+	showSetup = func() {
+		if stop != nil {
+			close(stop)
+			stop = nil
+		}
+		w.SetContent(container.NewVBox(
+			widget.NewLabel(fmt.Sprintf("选择冲刺时长，时间到之前尽量多地答题（%s）：", length)),
+			durationRadio,
+			widget.NewButton("开始冲刺", func() {
+				for i, o := range options {
+					if o == durationRadio.Selected {
+						start(sprintDurations[i])
+					}
+				}
+			}),
+			backBtn,
+		))
+	}
+
+	showSetup()
+	w.Resize(fyne.NewSize(400, 360))
+	w.Show()
+}
@@ -0,0 +1,215 @@
+package fifty_sounds
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ======================= 笔顺动画 =======================
+
+const strokeAnimDuration = 700 * time.Millisecond // 每一笔的播放时长
+
+var (
+	guideColor      = color.NRGBA{R: 0xd0, G: 0x30, B: 0x30, A: 0xff}
+	guideStartColor = color.NRGBA{R: 0x20, G: 0x80, B: 0xd0, A: 0xff}
+)
+
+const (
+	guideStrokeWidth = 6
+	guideStartDot    = 10 // 起笔圆点的直径
+)
+
+// SetGuide 在画布上叠加标准笔画：前 done 笔完整显示，第 done 笔按 progress(0~1) 显示一部分
+func (d *drawingWidget) SetGuide(strokes []stroke, done int, progress float32) {
+	d.guide = strokes
+	d.guideDone = done
+	d.guideProgress = progress
+	d.layoutGuide(d.Size())
+}
+
+// layoutGuide 把 100x100 的标准坐标映射到画布中央的正方形区域，重新生成演示线条
+func (d *drawingWidget) layoutGuide(size fyne.Size) {
+	if d.renderer == nil {
+		return
+	}
+	side := min(size.Width, size.Height) * 0.9
+	offX := (size.Width - side) / 2
+	offY := (size.Height - side) / 2
+	toCanvas := func(p fyne.Position) fyne.Position {
+		return fyne.NewPos(offX+p.X*side/100, offY+p.Y*side/100)
+	}
+
+	d.guideLines = nil
+	for i, st := range d.guide {
+		if i > d.guideDone {
+			break
+		}
+		part := st
+		if i == d.guideDone {
+			part = partialStroke(st, d.guideProgress)
+		}
+		for j := 1; j < len(part); j++ {
+			line := canvas.NewLine(guideColor)
+			line.StrokeWidth = guideStrokeWidth
+			line.Position1 = toCanvas(part[j-1])
+			line.Position2 = toCanvas(part[j])
+			d.guideLines = append(d.guideLines, line)
+		}
+		// 起笔处标一个小圆点，提示运笔方向
+		if len(st) > 0 {
+			start := toCanvas(st[0])
+			dot := canvas.NewCircle(guideStartColor)
+			dot.Move(fyne.NewPos(start.X-guideStartDot/2, start.Y-guideStartDot/2))
+			dot.Resize(fyne.NewSize(guideStartDot, guideStartDot))
+			d.guideLines = append(d.guideLines, dot)
+		}
+	}
+
+	objs := []fyne.CanvasObject{d.bg}
+	for _, l := range d.lines {
+		objs = append(objs, l)
+	}
+	d.renderer.objs = append(objs, d.guideLines...)
+	canvas.Refresh(d)
+}
+
+// partialStroke 截取笔画从起点开始、占总长度 t 的部分
+func partialStroke(st stroke, t float32) stroke {
+	if t >= 1 || len(st) < 2 {
+		return st
+	}
+	if t <= 0 {
+		return st[:1]
+	}
+	total := float32(0)
+	for i := 1; i < len(st); i++ {
+		total += distance(st[i-1], st[i])
+	}
+	want := total * t
+	res := stroke{st[0]}
+	for i := 1; i < len(st); i++ {
+		d := distance(st[i-1], st[i])
+		if d >= want {
+			r := want / d
+			res = append(res, fyne.NewPos(st[i-1].X+r*(st[i].X-st[i-1].X), st[i-1].Y+r*(st[i].Y-st[i-1].Y)))
+			break
+		}
+		want -= d
+		res = append(res, st[i])
+	}
+	return res
+}
+
+// strokeAnimator 在 drawingWidget 上逐笔演示某个假名的笔顺，支持重播和单步
+type strokeAnimator struct {
+	canvas  *drawingWidget
+	strokes []stroke
+	step    int
+	anim    *fyne.Animation
+	status  *widget.Label
+}
+
+func newStrokeAnimator(d *drawingWidget) *strokeAnimator {
+	return &strokeAnimator{
+		canvas: d,
+		status: widget.NewLabel(""),
+	}
+}
+
+// Play 从第一笔开始连续播放 kana 的笔顺，笔画取自 gojuon 中该假名所在的行（拗音等由基础假名组合），见 kanaStrokes
+func (a *strokeAnimator) Play(kana string) {
+	a.Stop()
+	a.strokes = kanaStrokes(kana)
+	if len(a.strokes) == 0 {
+		a.status.SetText("暂无该假名的笔顺数据")
+		return
+	}
+	a.start()
+}
+
+// Replay 重新播放当前假名
+func (a *strokeAnimator) Replay() {
+	if len(a.strokes) == 0 {
+		return
+	}
+	a.stopAnim()
+	a.start()
+}
+
+func (a *strokeAnimator) start() {
+	n := len(a.strokes)
+	a.anim = fyne.NewAnimation(time.Duration(n)*strokeAnimDuration, func(p float32) {
+		pos := p * float32(n)
+		done := int(pos)
+		if done >= n {
+			a.showStep(n)
+			return
+		}
+		a.step = done
+		a.canvas.SetGuide(a.strokes, done, pos-float32(done))
+		a.status.SetText(a.stepText(done + 1))
+	})
+	a.anim.Curve = fyne.AnimationLinear
+	a.anim.Start()
+}
+
+// Next 停止播放并多显示一笔
+func (a *strokeAnimator) Next() {
+	if len(a.strokes) == 0 {
+		return
+	}
+	a.stopAnim()
+	a.showStep(min(a.step+1, len(a.strokes)))
+}
+
+// Prev 停止播放并少显示一笔
+func (a *strokeAnimator) Prev() {
+	if len(a.strokes) == 0 {
+		return
+	}
+	a.stopAnim()
+	a.showStep(max(a.step-1, 0))
+}
+
+// Stop 停止播放并清除演示数据（不清除画布）
+func (a *strokeAnimator) Stop() {
+	a.stopAnim()
+	a.strokes = nil
+	a.step = 0
+	a.status.SetText("")
+}
+
+func (a *strokeAnimator) stopAnim() {
+	if a.anim != nil {
+		a.anim.Stop()
+		a.anim = nil
+	}
+}
+
+// showStep 完整显示前 step 笔
+func (a *strokeAnimator) showStep(step int) {
+	a.step = step
+	a.canvas.SetGuide(a.strokes, step, 0)
+	a.status.SetText(a.stepText(step))
+}
+
+func (a *strokeAnimator) stepText(step int) string {
+	return fmt.Sprintf("笔顺演示: 第 %d / %d 画", step, len(a.strokes))
+}
+
+// Controls 返回重播、上一笔、下一笔按钮和进度文字
+func (a *strokeAnimator) Controls() fyne.CanvasObject {
+	return container.NewHBox(
+		widget.NewButtonWithIcon("重播笔顺", theme.MediaReplayIcon(), a.Replay),
+		widget.NewButtonWithIcon("上一笔", theme.MediaSkipPreviousIcon(), a.Prev),
+		widget.NewButtonWithIcon("下一笔", theme.MediaSkipNextIcon(), a.Next),
+		a.status,
+	)
+}
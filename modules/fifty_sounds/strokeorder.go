@@ -0,0 +1,111 @@
+package fifty_sounds
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ======================= 笔画数、笔顺、运笔方向检查 =======================
+
+const (
+	reverseRatio = 0.6 // 反向距离小于正向距离的该比例时判定为方向写反
+	orderRatio   = 0.6 // 与其它笔画的距离小于与本笔的该比例时判定为笔顺错误
+)
+
+// strokeDirectionText 根据标准笔画起点到终点的主要走向给出方向描述
+func strokeDirectionText(st stroke) string {
+	if len(st) < 2 {
+		return ""
+	}
+	dx := st[len(st)-1].X - st[0].X
+	dy := st[len(st)-1].Y - st[0].Y
+	if math.Abs(float64(dx)) >= math.Abs(float64(dy)) {
+		if dx >= 0 {
+			return "从左往右"
+		}
+		return "从右往左"
+	}
+	if dy >= 0 {
+		return "从上往下"
+	}
+	return "从下往上"
+}
+
+// checkStrokeOrder 把手写笔画与 kana 的标准笔画逐笔比较，返回发现的问题；没有问题时返回 nil
+func checkStrokeOrder(drawn []drawnStroke, kana string) []string {
+	ref := kanaStrokes(kana)
+	if len(ref) == 0 {
+		return nil
+	}
+
+	// 按落笔时间排序，并去掉误触留下的单点
+	sorted := make([]drawnStroke, 0, len(drawn))
+	for _, ds := range drawn {
+		if len(ds.points) < 2 {
+			continue
+		}
+		sorted = append(sorted, ds)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].times[0].Before(sorted[j].times[0])
+	})
+
+	if len(sorted) != len(ref) {
+		return []string{fmt.Sprintf("笔画数不对：%s 应为 %d 画，实际写了 %d 画", kana, len(ref), len(sorted))}
+	}
+
+	input := make([]stroke, len(sorted))
+	for i, ds := range sorted {
+		input[i] = ds.points
+	}
+
+	// 两边分别整体归一化，保证每一笔在字中的相对位置可比
+	in := normalizeStrokes(input)
+	tpl := normalizeStrokes(ref)
+
+	var problems []string
+	for i := range in {
+		forward := strokeDistance(in[i], tpl[i])
+
+		bestOther, bestDist := -1, forward
+		for j := range tpl {
+			if j == i {
+				continue
+			}
+			if d := strokeDistance(in[i], tpl[j]); d < forward*orderRatio && d < bestDist {
+				bestOther, bestDist = j, d
+			}
+		}
+		if bestOther >= 0 {
+			problems = append(problems, fmt.Sprintf("第 %d 画笔顺不对，它应该是第 %d 画", i+1, bestOther+1))
+			continue
+		}
+
+		if strokeDistance(reverseStroke(in[i]), tpl[i]) < forward*reverseRatio {
+			problems = append(problems, fmt.Sprintf("第 %d 画方向写反了，应%s写", i+1, strokeDirectionText(ref[i])))
+		}
+	}
+	return problems
+}
+
+// strokeDistance 两条已重采样笔画对应点的平均距离
+func strokeDistance(a, b stroke) float32 {
+	n := min(len(a), len(b))
+	if n == 0 {
+		return float32(math.MaxFloat32)
+	}
+	sum := float32(0)
+	for i := 0; i < n; i++ {
+		sum += distance(a[i], b[i])
+	}
+	return sum / float32(n)
+}
+
+func reverseStroke(st stroke) stroke {
+	res := make(stroke, len(st))
+	for i, p := range st {
+		res[len(st)-1-i] = p
+	}
+	return res
+}
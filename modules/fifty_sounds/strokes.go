@@ -0,0 +1,171 @@
+package fifty_sounds
+
+import (
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// ======================= 假名笔画模板 =======================
+// 坐标系为 100x100 的方格，x 向右，y 向下。
+// 每个字符串是一笔，按书写顺序排列，点的顺序即运笔方向。
+// 清音、古假名和长音的笔画放在 gojuon 各行的 hiraganaStrokes、katakanaStrokes 中，
+// 浊音、半浊音、拗音、小写假名和外来音由基础假名组合生成，见 kanaStrokes。
+
+type stroke []fyne.Position
+
+// 浊点、半浊点，位于字的右上角
+var (
+	dakutenStrokes    = []string{"80,10 86,24", "90,6 96,20"}
+	handakutenStrokes = []string{"90,8 84,12 85,20 92,22 96,15 90,8"}
+)
+
+// 浊音行 => 对应的清音行
+var voicedLineBase = map[string]string{
+	"ga": "ka",
+	"za": "sa",
+	"da": "ta",
+	"ba": "ha",
+	"pa": "ha",
+}
+
+// 小写假名 => 对应的大写假名
+var smallKanaBase = map[string]string{
+	"ゃ": "や", "ゅ": "ゆ", "ょ": "よ",
+	"ャ": "ヤ", "ュ": "ユ", "ョ": "ヨ",
+	"ぁ": "あ", "ぃ": "い", "ぅ": "う", "ぇ": "え", "ぉ": "お", "ゎ": "わ", "っ": "つ",
+	"ァ": "ア", "ィ": "イ", "ゥ": "ウ", "ェ": "エ", "ォ": "オ", "ヮ": "ワ", "ッ": "ツ",
+}
+
+// 不在浊音行中的浊音 => 对应的清音
+var voicedExtra = map[string]string{"ゔ": "う", "ヴ": "ウ"}
+
+var strokeCache = make(map[string][]stroke)
+
+func parseStroke(s string) stroke {
+	var st stroke
+	for _, pair := range strings.Fields(s) {
+		xy := strings.Split(pair, ",")
+		if len(xy) != 2 {
+			continue
+		}
+		x, errX := strconv.ParseFloat(xy[0], 32)
+		y, errY := strconv.ParseFloat(xy[1], 32)
+		if errX != nil || errY != nil {
+			continue
+		}
+		st = append(st, fyne.NewPos(float32(x), float32(y)))
+	}
+	return st
+}
+
+func parseStrokes(ss []string) []stroke {
+	res := make([]stroke, 0, len(ss))
+	for _, s := range ss {
+		res = append(res, parseStroke(s))
+	}
+	return res
+}
+
+// 把笔画缩放并平移到 (x, y, x+size, y+size) 区域
+func placeStrokes(src []stroke, x, y, size float32) []stroke {
+	res := make([]stroke, 0, len(src))
+	for _, st := range src {
+		moved := make(stroke, len(st))
+		for i, p := range st {
+			moved[i] = fyne.NewPos(x+p.X*size/100, y+p.Y*size/100)
+		}
+		res = append(res, moved)
+	}
+	return res
+}
+
+// kanaStrokes 返回某个假名（含浊音、半浊音、拗音）的标准笔画，没有数据时返回 nil
+func kanaStrokes(kana string) []stroke {
+	if st, ok := strokeCache[kana]; ok {
+		return st
+	}
+	st := buildKanaStrokes(kana)
+	strokeCache[kana] = st
+	return st
+}
+
+func buildKanaStrokes(kana string) []stroke {
+	if ss := lineStrokes(kana); ss != nil {
+		return parseStrokes(ss)
+	}
+
+	// 单独的小写假名：大写假名缩小后放在左下
+	if big, ok := smallKanaBase[kana]; ok {
+		return placeStrokes(kanaStrokes(big), 12, 35, 60)
+	}
+	if base, ok := voicedExtra[kana]; ok {
+		return append(placeStrokes(kanaStrokes(base), 0, 5, 88), parseStrokes(dakutenStrokes)...)
+	}
+
+	// 拗音和外来音：大写假名在左，小写假名缩小后放在右下
+	runes := []rune(kana)
+	if len(runes) == 2 {
+		if big, ok := smallKanaBase[string(runes[1])]; ok {
+			first := kanaStrokes(string(runes[0]))
+			second := kanaStrokes(big)
+			if first == nil || second == nil {
+				return nil
+			}
+			res := placeStrokes(first, 0, 10, 62)
+			return append(res, placeStrokes(second, 58, 45, 42)...)
+		}
+		return nil
+	}
+
+	// 浊音、半浊音：清音 + 浊点/半浊点
+	for _, line := range gojuon {
+		baseRomaji, ok := voicedLineBase[line.romaji]
+		if !ok {
+			continue
+		}
+		marks := dakutenStrokes
+		if line.romaji == "pa" {
+			marks = handakutenStrokes
+		}
+		for _, baseLine := range gojuon {
+			if baseLine.romaji != baseRomaji {
+				continue
+			}
+			if base := voicedBase(kana, line.hiragana, baseLine.hiragana); base != "" {
+				return append(placeStrokes(kanaStrokes(base), 0, 5, 88), parseStrokes(marks)...)
+			}
+			if base := voicedBase(kana, line.katakana, baseLine.katakana); base != "" {
+				return append(placeStrokes(kanaStrokes(base), 0, 5, 88), parseStrokes(marks)...)
+			}
+		}
+	}
+	return nil
+}
+
+func voicedBase(kana string, voiced, base []string) string {
+	for i, v := range voiced {
+		if v == kana && i < len(base) {
+			return base[i]
+		}
+	}
+	return ""
+}
+
+// lineStrokes 在 gojuon 中查找 kana 所在行附带的笔画，没有时返回 nil
+func lineStrokes(kana string) []string {
+	for _, line := range gojuon {
+		for i, h := range line.hiragana {
+			if h == kana && i < len(line.hiraganaStrokes) {
+				return line.hiraganaStrokes[i]
+			}
+		}
+		for i, k := range line.katakana {
+			if k == kana && i < len(line.katakanaStrokes) {
+				return line.katakanaStrokes[i]
+			}
+		}
+	}
+	return nil
+}
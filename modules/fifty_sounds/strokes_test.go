@@ -0,0 +1,51 @@
+package fifty_sounds
+
+import "testing"
+
+func TestGojuonStrokesParallel(t *testing.T) {
+	for _, line := range gojuon {
+		if n := len(line.hiraganaStrokes); n > 0 && n != len(line.hiragana) {
+			t.Errorf("%s: %d hiragana, %d hiraganaStrokes", line.label(), len(line.hiragana), n)
+		}
+		if n := len(line.katakanaStrokes); n > 0 && n != len(line.katakana) {
+			t.Errorf("%s: %d katakana, %d katakanaStrokes", line.label(), len(line.katakana), n)
+		}
+	}
+}
+
+// 笔顺动画和笔顺检查要覆盖五十音图中的每个假名，包括拗音和外来音
+func TestEveryKanaHasStrokes(t *testing.T) {
+	for _, line := range gojuon {
+		for _, kana := range append(append([]string{}, line.hiragana...), line.katakana...) {
+			st := kanaStrokes(kana)
+			if len(st) == 0 {
+				t.Errorf("%s (%s): no strokes", kana, line.label())
+			}
+			for i, s := range st {
+				if len(s) < 2 {
+					t.Errorf("%s: stroke %d has %d points", kana, i+1, len(s))
+				}
+			}
+		}
+	}
+}
+
+func TestComposedStrokes(t *testing.T) {
+	tests := []struct {
+		kana string
+		want int
+	}{
+		{"あ", 3},
+		{"が", 5},  // か + 浊点
+		{"ぱ", 4},  // は + 半浊点
+		{"きゃ", 7}, // き + 小写 や
+		{"ッ", 3},
+		{"ヴ", 5},
+		{"ティ", 5},
+	}
+	for _, tt := range tests {
+		if got := len(kanaStrokes(tt.kana)); got != tt.want {
+			t.Errorf("len(kanaStrokes(%q)) = %d, want %d", tt.kana, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package history
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"FiftySound/modules/appdata"
+)
+
+// ==================================================
+// 答题记录：两个模块的每一次判题都记录在这里，并保存在应用存储中。
+// 记录越来越多，每次判题都写一遍文件太慢，所以新记录攒一会儿再在后台一起保存，
+// 退出程序前调用 Flush 写入剩下的记录
+// ==================================================
+
+const (
+	historyFile = "history.json"
+	saveDelay   = 3 * time.Second // 新记录最多等多久写入文件
+)
+
+// DayLayout 是 AccuracyByDay 返回的日期格式
+const DayLayout = "2006-01-02"
+
+// 模块名
+const (
+	ModuleKana = "kana" // 五十音
+	ModuleWord = "word" // 单词
+)
+
+// Record 是一次判题的记录
+type Record struct {
+	Time    time.Time     `json:"time"`
+	Module  string        `json:"module"`
+	Mode    string        `json:"mode"`
+	Item    string        `json:"item"`  // 假名，或单词的 假名|汉字
+	Group   string        `json:"group"` // 五十音的行（如 "ka行"），或单词所在的单元文件
+	Answer  string        `json:"answer"`
+	Correct bool          `json:"correct"`
+	Latency time.Duration `json:"latency"` // 从出题到判题的用时
+}
+
+// Filter 查询条件，空字段表示不限制
+type Filter struct {
+	Module string
+	Mode   string
+	Item   string
+	Group  string
+	Since  time.Time // 包含
+	Until  time.Time // 不包含
+}
+
+func (f Filter) match(r Record) bool {
+	if f.Module != "" && r.Module != f.Module {
+		return false
+	}
+	if f.Mode != "" && r.Mode != f.Mode {
+		return false
+	}
+	if f.Item != "" && r.Item != f.Item {
+		return false
+	}
+	if f.Group != "" && r.Group != f.Group {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !r.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Accuracy 答题数与正确数
+type Accuracy struct {
+	Total   int
+	Correct int
+}
+
+// Rate 正确率，百分比
+func (a Accuracy) Rate() float64 {
+	if a.Total == 0 {
+		return 0
+	}
+	return float64(a.Correct) / float64(a.Total) * 100.0
+}
+
+func (a *Accuracy) add(r Record) {
+	a.Total++
+	if r.Correct {
+		a.Correct++
+	}
+}
+
+// Log 所有答题记录
+type Log struct {
+	mu      sync.Mutex
+	app     fyne.App
+	records []Record
+	pending bool // 有还没写入文件的记录，已经安排了保存
+
+	saveMu sync.Mutex // 保证先取的快照先写入
+}
+
+var (
+	shared     *Log
+	sharedOnce sync.Once
+)
+
+// Open 返回两个模块共用的答题记录，首次调用时从应用存储中读取
+func Open(a fyne.App) *Log {
+	sharedOnce.Do(func() {
+		shared = &Log{app: a}
+		if err := appdata.LoadJSON(a, historyFile, &shared.records); err != nil {
+			fyne.LogError("读取答题记录失败", err)
+		}
+	})
+	return shared
+}
+
+// Add 追加一条记录，Time 为空时使用当前时间；记录在 saveDelay 内于后台保存
+func (l *Log) Add(r Record) {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, r)
+	if !l.pending {
+		l.pending = true
+		time.AfterFunc(saveDelay, l.Flush)
+	}
+}
+
+// Flush 立即保存还没写入文件的记录
+func (l *Log) Flush() {
+	l.saveMu.Lock()
+	defer l.saveMu.Unlock()
+
+	l.mu.Lock()
+	if !l.pending {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = false
+	// 之后的 Add 只会追加，不会修改快照中已有的记录
+	snapshot := l.records[:len(l.records):len(l.records)]
+	l.mu.Unlock()
+
+	data, err := json.Marshal(snapshot)
+	if err == nil {
+		err = appdata.WriteFile(l.app, historyFile, data)
+	}
+	if err != nil {
+		fyne.LogError("保存答题记录失败", err)
+	}
+}
+
+// Query 返回符合条件的记录，按时间先后排列
+func (l *Log) Query(f Filter) []Record {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var res []Record
+	for _, r := range l.records {
+		if f.match(r) {
+			res = append(res, r)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Time.Before(res[j].Time)
+	})
+	return res
+}
+
+// Accuracy 符合条件的记录的总正确率
+func (l *Log) Accuracy(f Filter) Accuracy {
+	var acc Accuracy
+	for _, r := range l.Query(f) {
+		acc.add(r)
+	}
+	return acc
+}
+
+// AccuracyByItem 按题目（每个假名、每个单词）统计正确率
+func (l *Log) AccuracyByItem(f Filter) map[string]Accuracy {
+	return l.groupBy(f, func(r Record) string { return r.Item })
+}
+
+// AccuracyByGroup 按五十音的行或单词的单元统计正确率
+func (l *Log) AccuracyByGroup(f Filter) map[string]Accuracy {
+	return l.groupBy(f, func(r Record) string { return r.Group })
+}
+
+// AccuracyByDay 按本地日期（如 "2024-05-01"）统计正确率
+func (l *Log) AccuracyByDay(f Filter) map[string]Accuracy {
+	return l.groupBy(f, func(r Record) string { return r.Time.Local().Format(DayLayout) })
+}
+
+func (l *Log) groupBy(f Filter, key func(Record) string) map[string]Accuracy {
+	res := make(map[string]Accuracy)
+	for _, r := range l.Query(f) {
+		acc := res[key(r)]
+		acc.add(r)
+		res[key(r)] = acc
+	}
+	return res
+}
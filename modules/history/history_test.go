@@ -0,0 +1,123 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+
+	"FiftySound/modules/appdata"
+)
+
+var day0 = time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+
+func testRecords() []Record {
+	return []Record{
+		{Time: day0, Module: ModuleKana, Mode: "mode1", Item: "あ", Group: "a行", Correct: true},
+		{Time: day0.Add(time.Hour), Module: ModuleKana, Mode: "mode1", Item: "か", Group: "ka行", Correct: false},
+		{Time: day0.Add(2 * time.Hour), Module: ModuleKana, Mode: "mode2", Item: "あ", Group: "a行", Correct: true},
+		{Time: day0.Add(24 * time.Hour), Module: ModuleWord, Mode: "mode1", Item: "ねこ|猫", Group: "unit1.json", Correct: false},
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	r := testRecords()[0]
+	tests := []struct {
+		name string
+		f    Filter
+		want bool
+	}{
+		{"empty", Filter{}, true},
+		{"module", Filter{Module: ModuleKana}, true},
+		{"other module", Filter{Module: ModuleWord}, false},
+		{"mode", Filter{Mode: "mode2"}, false},
+		{"item", Filter{Item: "あ"}, true},
+		{"group", Filter{Group: "ka行"}, false},
+		{"since is inclusive", Filter{Since: day0}, true},
+		{"since after", Filter{Since: day0.Add(time.Second)}, false},
+		{"until is exclusive", Filter{Until: day0}, false},
+		{"until after", Filter{Until: day0.Add(time.Second)}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.f.match(r); got != tt.want {
+			t.Errorf("%s: match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAccuracyRate(t *testing.T) {
+	tests := []struct {
+		acc  Accuracy
+		want float64
+	}{
+		{Accuracy{}, 0},
+		{Accuracy{Total: 4, Correct: 1}, 25},
+		{Accuracy{Total: 3, Correct: 3}, 100},
+	}
+	for _, tt := range tests {
+		if got := tt.acc.Rate(); got != tt.want {
+			t.Errorf("%+v.Rate() = %v, want %v", tt.acc, got, tt.want)
+		}
+	}
+}
+
+func TestAccuracyBy(t *testing.T) {
+	l := &Log{records: testRecords()}
+
+	if got := l.Accuracy(Filter{}); got != (Accuracy{Total: 4, Correct: 2}) {
+		t.Errorf("Accuracy = %+v", got)
+	}
+	byItem := l.AccuracyByItem(Filter{Module: ModuleKana})
+	if len(byItem) != 2 || byItem["あ"] != (Accuracy{2, 2}) || byItem["か"] != (Accuracy{1, 0}) {
+		t.Errorf("AccuracyByItem = %v", byItem)
+	}
+	byGroup := l.AccuracyByGroup(Filter{Mode: "mode1"})
+	if len(byGroup) != 3 || byGroup["a行"] != (Accuracy{1, 1}) || byGroup["unit1.json"] != (Accuracy{1, 0}) {
+		t.Errorf("AccuracyByGroup = %v", byGroup)
+	}
+	byDay := l.AccuracyByDay(Filter{})
+	if len(byDay) != 2 || byDay["2024-05-01"] != (Accuracy{3, 2}) || byDay["2024-05-02"] != (Accuracy{1, 0}) {
+		t.Errorf("AccuracyByDay = %v", byDay)
+	}
+}
+
+func TestQuerySortsByTime(t *testing.T) {
+	records := testRecords()
+	records[0], records[3] = records[3], records[0]
+	l := &Log{records: records}
+	res := l.Query(Filter{})
+	for i := 1; i < len(res); i++ {
+		if res[i].Time.Before(res[i-1].Time) {
+			t.Fatalf("Query not sorted: %v", res)
+		}
+	}
+}
+
+// Add 只安排保存，Flush 后文件中才有全部记录
+func TestAddFlush(t *testing.T) {
+	a := test.NewTempApp(t)
+	l := &Log{app: a}
+	for _, r := range testRecords() {
+		l.Add(r)
+	}
+	if !l.pending {
+		t.Fatal("Add did not schedule a save")
+	}
+	l.Flush()
+	if l.pending {
+		t.Error("pending after Flush")
+	}
+
+	var saved []Record
+	if err := appdata.LoadJSON(a, historyFile, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != len(testRecords()) {
+		t.Fatalf("saved %d records, want %d", len(saved), len(testRecords()))
+	}
+	for i, r := range testRecords() {
+		if saved[i].Item != r.Item || !saved[i].Time.Equal(r.Time) || saved[i].Correct != r.Correct {
+			t.Errorf("record %d = %+v, want %+v", i, saved[i], r)
+		}
+	}
+}
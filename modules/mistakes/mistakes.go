@@ -0,0 +1,163 @@
+package mistakes
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"FiftySound/modules/appdata"
+)
+
+// ==================================================
+// 错题本：两个模块共用。答错的题目记入错题本，在"只练错题"中
+// 连续答对一定次数（可设置）后移出；保存在应用存储中，重启后保留
+// ==================================================
+
+const bookFile = "mistakes.json"
+
+// Entry 错题本中的一道题，同一模块、同一模式下的同一项目只记一条
+type Entry struct {
+	Module   string          `json:"module"` // history.ModuleKana 或 history.ModuleWord
+	Mode     string          `json:"mode"`
+	Item     string          `json:"item"`  // 假名，或单词的 假名|汉字
+	Group    string          `json:"group"` // 五十音的行，或单词所在的单元
+	Question string          `json:"question"`
+	Answer   string          `json:"answer"` // 最近一次答错时的作答
+	Expected string          `json:"expected"`
+	Data     json.RawMessage `json:"data,omitempty"` // 模块重新出题需要的数据，如单词的完整内容
+	Misses   int             `json:"misses"`
+	Streak   int             `json:"streak"` // 只练错题时连续答对的次数
+	Time     time.Time       `json:"time"`   // 最近一次答错的时间
+}
+
+func (e Entry) same(module, mode, item string) bool {
+	return e.Module == module && e.Mode == mode && e.Item == item
+}
+
+// Book 错题本
+type Book struct {
+	mu      sync.Mutex
+	app     fyne.App
+	entries []Entry
+}
+
+var (
+	shared     *Book
+	sharedOnce sync.Once
+)
+
+// Open 返回两个模块共用的错题本，首次调用时从应用存储中读取
+func Open(a fyne.App) *Book {
+	sharedOnce.Do(func() {
+		shared = &Book{app: a}
+		if err := appdata.LoadJSON(a, bookFile, &shared.entries); err != nil {
+			fyne.LogError("读取错题本失败", err)
+		}
+	})
+	return shared
+}
+
+// Miss 记入一道答错的题：已有时更新作答、次数并把连对次数清零
+func (b *Book) Miss(e Entry) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Streak = 0
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.entries {
+		old := &b.entries[i]
+		if old.same(e.Module, e.Mode, e.Item) {
+			e.Misses = old.Misses + 1
+			*old = e
+			b.save()
+			return
+		}
+	}
+	e.Misses = 1
+	b.entries = append(b.entries, e)
+	b.save()
+}
+
+// Hit 只练错题时答对一次；连续答对达到 ClearAfter 次后移出错题本，cleared 为 true
+func (b *Book) Hit(module, mode, item string) (cleared bool) {
+	need := ClearAfter(b.app)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.entries {
+		e := &b.entries[i]
+		if !e.same(module, mode, item) {
+			continue
+		}
+		e.Streak++
+		if e.Streak >= need {
+			b.entries = append(b.entries[:i], b.entries[i+1:]...)
+			cleared = true
+		}
+		b.save()
+		return cleared
+	}
+	return false
+}
+
+// Remove 把一道题移出错题本
+func (b *Book) Remove(module, mode, item string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, e := range b.entries {
+		if e.same(module, mode, item) {
+			b.entries = append(b.entries[:i], b.entries[i+1:]...)
+			b.save()
+			return
+		}
+	}
+}
+
+// Entries 返回 module、mode 下的错题（空字符串表示不限），按模块、模式、分组排列
+func (b *Book) Entries(module, mode string) []Entry {
+	b.mu.Lock()
+	var res []Entry
+	for _, e := range b.entries {
+		if (module == "" || e.Module == module) && (mode == "" || e.Mode == mode) {
+			res = append(res, e)
+		}
+	}
+	b.mu.Unlock()
+
+	sort.SliceStable(res, func(i, j int) bool {
+		a, c := res[i], res[j]
+		if a.Module != c.Module {
+			return a.Module < c.Module
+		}
+		if a.Mode != c.Mode {
+			return a.Mode < c.Mode
+		}
+		return a.Group < c.Group
+	})
+	return res
+}
+
+// save 调用时需持有 mu
+func (b *Book) save() {
+	if err := appdata.SaveJSON(b.app, bookFile, b.entries); err != nil {
+		fyne.LogError("保存错题本失败", err)
+	}
+}
+
+const clearAfterPrefKey = "mistakes.clear_after"
+
+// DefaultClearAfter 默认连续答对 3 次后移出错题本
+const DefaultClearAfter = 3
+
+// ClearAfter 只练错题时连续答对几次后移出错题本
+func ClearAfter(a fyne.App) int {
+	return max(1, a.Preferences().IntWithFallback(clearAfterPrefKey, DefaultClearAfter))
+}
+
+// SetClearAfter 修改移出错题本需要的连对次数
+func SetClearAfter(a fyne.App, n int) {
+	a.Preferences().SetInt(clearAfterPrefKey, n)
+}
@@ -0,0 +1,197 @@
+package notebook
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/fifty_sounds"
+	"FiftySound/modules/history"
+	"FiftySound/modules/mistakes"
+	"FiftySound/modules/vocabulary"
+)
+
+// ==================================================
+// 错题本窗口：按 模块/模式 => 行或单元 => 错题 浏览两个模块的错题，
+// 可以只练选中的一组错题，或把错题移出错题本
+// ==================================================
+
+// 树节点 ID 的各部分用 sep 连接，第一部分为节点类型
+const (
+	sep       = "\x1f"
+	kindMode  = "m" // 模块/模式
+	kindGroup = "g" // 行或单元
+	kindEntry = "e" // 一道错题
+)
+
+var moduleNames = map[string]string{
+	history.ModuleKana: "五十音",
+	history.ModuleWord: "单词",
+}
+
+// index 错题本的树形索引
+type index struct {
+	children map[widget.TreeNodeID][]widget.TreeNodeID
+	entries  map[widget.TreeNodeID]mistakes.Entry // 只有错题节点
+}
+
+func nodeID(kind string, parts ...string) widget.TreeNodeID {
+	return strings.Join(append([]string{kind}, parts...), sep)
+}
+
+func buildIndex(entries []mistakes.Entry) *index {
+	idx := &index{
+		children: make(map[widget.TreeNodeID][]widget.TreeNodeID),
+		entries:  make(map[widget.TreeNodeID]mistakes.Entry),
+	}
+	add := func(parent, child widget.TreeNodeID) {
+		for _, c := range idx.children[parent] {
+			if c == child {
+				return
+			}
+		}
+		idx.children[parent] = append(idx.children[parent], child)
+	}
+	for _, e := range entries {
+		m := nodeID(kindMode, e.Module, e.Mode)
+		g := nodeID(kindGroup, e.Module, e.Mode, e.Group)
+		id := nodeID(kindEntry, e.Module, e.Mode, e.Group, e.Item)
+		add("", m)
+		add(m, g)
+		add(g, id)
+		idx.entries[id] = e
+	}
+	return idx
+}
+
+// under 节点下的所有错题
+func (idx *index) under(id widget.TreeNodeID) []mistakes.Entry {
+	if e, ok := idx.entries[id]; ok {
+		return []mistakes.Entry{e}
+	}
+	var res []mistakes.Entry
+	for _, c := range idx.children[id] {
+		res = append(res, idx.under(c)...)
+	}
+	return res
+}
+
+func (idx *index) label(id widget.TreeNodeID, clearAfter int) string {
+	parts := strings.Split(id, sep)
+	n := len(idx.under(id))
+	switch parts[0] {
+	case kindMode:
+		return fmt.Sprintf("%s - %s (%d)", moduleNames[parts[1]], parts[2], n)
+	case kindGroup:
+		group := parts[3]
+		if group == "" {
+			group = "未分组"
+		}
+		return fmt.Sprintf("%s (%d)", group, n)
+	}
+	e := idx.entries[id]
+	return fmt.Sprintf("%s    你的答案: %s    正确答案: %s    错 %d 次，连对 %d/%d",
+		e.Question, e.Answer, e.Expected, e.Misses, e.Streak, clearAfter)
+}
+
+// ShowNotebook 打开错题本窗口
+func ShowNotebook(myApp fyne.App, parent fyne.Window) {
+	win := myApp.NewWindow("错题本")
+	book := mistakes.Open(myApp)
+
+	idx := buildIndex(book.Entries("", ""))
+	selected := ""
+
+	tree := widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID { return idx.children[id] },
+		func(id widget.TreeNodeID) bool { return len(idx.children[id]) > 0 || id == "" },
+		func(bool) fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TreeNodeID, _ bool, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(idx.label(id, mistakes.ClearAfter(myApp)))
+		},
+	)
+	tree.OnSelected = func(id widget.TreeNodeID) { selected = id }
+	tree.OnUnselected = func(widget.TreeNodeID) { selected = "" }
+
+	summary := widget.NewLabel("")
+	refresh := func() {
+		entries := book.Entries("", "")
+		idx = buildIndex(entries)
+		selected = ""
+		tree.UnselectAll()
+		tree.Refresh()
+		for _, m := range idx.children[""] {
+			tree.OpenBranch(m)
+		}
+		if len(entries) == 0 {
+			summary.SetText("错题本是空的")
+		} else {
+			summary.SetText(fmt.Sprintf("共 %d 道错题，选中一个模式、一行/单元或一道题后可以只练这些错题", len(entries)))
+		}
+	}
+
+	practiceBtn := widget.NewButton("只练错题", func() {
+		entries := idx.under(selected)
+		if selected == "" || len(entries) == 0 {
+			dialog.ShowInformation("提示", "请先选择要练习的错题（一个模式、一行/单元或一道题）", win)
+			return
+		}
+		switch entries[0].Module {
+		case history.ModuleKana:
+			fifty_sounds.PracticeMistakes(myApp, win, entries)
+		case history.ModuleWord:
+			vocabulary.PracticeMistakes(myApp, win, entries)
+		}
+	})
+	removeBtn := widget.NewButton("移出错题本", func() {
+		entries := idx.under(selected)
+		if selected == "" || len(entries) == 0 {
+			dialog.ShowInformation("提示", "请先选择要移出的错题", win)
+			return
+		}
+		dialog.ShowConfirm("移出错题本", fmt.Sprintf("确定把选中的 %d 道错题移出错题本吗？", len(entries)), func(ok bool) {
+			if !ok {
+				return
+			}
+			for _, e := range entries {
+				book.Remove(e.Module, e.Mode, e.Item)
+			}
+			refresh()
+		}, win)
+	})
+	refreshBtn := widget.NewButton("刷新", refresh)
+
+	// 只练错题时连续答对几次后移出
+	var options []string
+	for n := 1; n <= 5; n++ {
+		options = append(options, strconv.Itoa(n))
+	}
+	clearSelect := widget.NewSelect(options, func(s string) {
+		n, _ := strconv.Atoi(s)
+		mistakes.SetClearAfter(myApp, n)
+		tree.Refresh()
+	})
+	clearSelect.SetSelected(strconv.Itoa(mistakes.ClearAfter(myApp)))
+
+	closeBtn := widget.NewButton("关闭", func() {
+		win.Close()
+	})
+
+	win.SetContent(container.NewBorder(
+		container.NewVBox(
+			summary,
+			container.NewHBox(widget.NewLabel("只练错题时连续答对"), clearSelect, widget.NewLabel("次后移出错题本")),
+		),
+		container.NewHBox(practiceBtn, removeBtn, refreshBtn, closeBtn),
+		nil, nil,
+		tree,
+	))
+	refresh()
+	win.Resize(fyne.NewSize(760, 520))
+	win.Show()
+}
@@ -0,0 +1,61 @@
+package romaji
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// KanaEntry 单行输入框，输入罗马音时实时转换为假名；
+// 还没输完的罗马音（如 "ky"、结尾的 "n"）先保留，失去焦点或取 Kana() 时再转换。
+// 已经是假名的字符（比如用系统输入法输入的）不受影响
+type KanaEntry struct {
+	widget.Entry
+	script Script
+}
+
+// NewKanaEntry 创建转换为 script 的输入框
+func NewKanaEntry(script Script) *KanaEntry {
+	e := &KanaEntry{script: script}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// SetScript 切换转换目标，已经输入的内容不变
+func (e *KanaEntry) SetScript(script Script) {
+	e.script = script
+}
+
+// TypedRune 每输入一个字符就转换光标前的内容
+func (e *KanaEntry) TypedRune(r rune) {
+	e.Entry.TypedRune(r)
+	e.convert(false)
+}
+
+// FocusLost 离开输入框时把剩下的罗马音也转换掉
+func (e *KanaEntry) FocusLost() {
+	e.convert(true)
+	e.Entry.FocusLost()
+}
+
+// Kana 返回完全转换后的内容（去掉首尾空白）
+func (e *KanaEntry) Kana() string {
+	return strings.TrimSpace(ToKana(e.Text, e.script))
+}
+
+// convert 转换光标前的文本，光标后的部分不动
+func (e *KanaEntry) convert(final bool) {
+	if e.script == Off || e.MultiLine {
+		return
+	}
+	text := []rune(e.Text)
+	col := min(e.CursorColumn, len(text))
+	before := string(text[:col])
+	converted, _ := convert(before, e.script, final)
+	if converted == before {
+		return
+	}
+	e.SetText(converted + string(text[col:]))
+	e.CursorColumn = len([]rune(converted))
+	e.Refresh()
+}
@@ -0,0 +1,255 @@
+package romaji
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ==================================================
+// 罗马音 => 假名：同时接受平文式 (Hepburn)、训令式 (Kunrei) 和日本式的写法，
+// 支持促音（双写辅音）、拨音 (n, n', nn)、长音（- 和带长音符号的元音）以及 x/l 开头的小写假名
+// ==================================================
+
+// Script 转换目标
+type Script int
+
+const (
+	Hiragana Script = iota // 平假名
+	Katakana               // 片假名
+	Off                    // 不转换
+)
+
+// romajiTable 罗马音到平假名，片假名由平假名换算
+var romajiTable = map[string]string{
+	"a": "あ", "i": "い", "u": "う", "e": "え", "o": "お",
+
+	"ka": "か", "ki": "き", "ku": "く", "ke": "け", "ko": "こ",
+	"kya": "きゃ", "kyi": "きぃ", "kyu": "きゅ", "kye": "きぇ", "kyo": "きょ",
+	"kwa": "くぁ", "qa": "くぁ", "qi": "くぃ", "qe": "くぇ", "qo": "くぉ",
+	"ga": "が", "gi": "ぎ", "gu": "ぐ", "ge": "げ", "go": "ご",
+	"gya": "ぎゃ", "gyi": "ぎぃ", "gyu": "ぎゅ", "gye": "ぎぇ", "gyo": "ぎょ", "gwa": "ぐぁ",
+
+	"sa": "さ", "si": "し", "shi": "し", "su": "す", "se": "せ", "so": "そ",
+	"sya": "しゃ", "syu": "しゅ", "sye": "しぇ", "syo": "しょ",
+	"sha": "しゃ", "shu": "しゅ", "she": "しぇ", "sho": "しょ",
+	"za": "ざ", "zi": "じ", "ji": "じ", "zu": "ず", "ze": "ぜ", "zo": "ぞ",
+	"zya": "じゃ", "zyu": "じゅ", "zye": "じぇ", "zyo": "じょ",
+	"ja": "じゃ", "ju": "じゅ", "je": "じぇ", "jo": "じょ",
+	"jya": "じゃ", "jyu": "じゅ", "jye": "じぇ", "jyo": "じょ",
+
+	"ta": "た", "ti": "ち", "chi": "ち", "tu": "つ", "tsu": "つ", "te": "て", "to": "と",
+	"tya": "ちゃ", "tyu": "ちゅ", "tye": "ちぇ", "tyo": "ちょ",
+	"cha": "ちゃ", "chu": "ちゅ", "che": "ちぇ", "cho": "ちょ",
+	"cya": "ちゃ", "cyu": "ちゅ", "cye": "ちぇ", "cyo": "ちょ",
+	"tsa": "つぁ", "tsi": "つぃ", "tse": "つぇ", "tso": "つぉ",
+	"thi": "てぃ", "thu": "てゅ", "twu": "とぅ",
+	"da": "だ", "di": "ぢ", "du": "づ", "de": "で", "do": "ど",
+	"dya": "ぢゃ", "dyu": "ぢゅ", "dye": "ぢぇ", "dyo": "ぢょ",
+	"dhi": "でぃ", "dhu": "でゅ", "dwu": "どぅ",
+
+	"na": "な", "ni": "に", "nu": "ぬ", "ne": "ね", "no": "の",
+	"nya": "にゃ", "nyi": "にぃ", "nyu": "にゅ", "nye": "にぇ", "nyo": "にょ",
+
+	"ha": "は", "hi": "ひ", "hu": "ふ", "fu": "ふ", "he": "へ", "ho": "ほ",
+	"hya": "ひゃ", "hyi": "ひぃ", "hyu": "ひゅ", "hye": "ひぇ", "hyo": "ひょ",
+	"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ",
+	"fya": "ふゃ", "fyu": "ふゅ", "fyo": "ふょ",
+	"ba": "ば", "bi": "び", "bu": "ぶ", "be": "べ", "bo": "ぼ",
+	"bya": "びゃ", "byi": "びぃ", "byu": "びゅ", "bye": "びぇ", "byo": "びょ",
+	"pa": "ぱ", "pi": "ぴ", "pu": "ぷ", "pe": "ぺ", "po": "ぽ",
+	"pya": "ぴゃ", "pyi": "ぴぃ", "pyu": "ぴゅ", "pye": "ぴぇ", "pyo": "ぴょ",
+	"va": "ゔぁ", "vi": "ゔぃ", "vu": "ゔ", "ve": "ゔぇ", "vo": "ゔぉ",
+	"vya": "ゔゃ", "vyu": "ゔゅ", "vyo": "ゔょ",
+
+	"ma": "ま", "mi": "み", "mu": "む", "me": "め", "mo": "も",
+	"mya": "みゃ", "myi": "みぃ", "myu": "みゅ", "mye": "みぇ", "myo": "みょ",
+	"ya": "や", "yu": "ゆ", "ye": "いぇ", "yo": "よ",
+	"ra": "ら", "ri": "り", "ru": "る", "re": "れ", "ro": "ろ",
+	"rya": "りゃ", "ryi": "りぃ", "ryu": "りゅ", "rye": "りぇ", "ryo": "りょ",
+	"wa": "わ", "wi": "うぃ", "we": "うぇ", "wo": "を",
+	"wha": "うぁ", "whi": "うぃ", "whu": "う", "whe": "うぇ", "who": "うぉ",
+	"wyi": "ゐ", "wye": "ゑ",
+
+	"ca": "か", "ci": "し", "cu": "く", "ce": "せ", "co": "こ",
+
+	// 小写假名
+	"xa": "ぁ", "xi": "ぃ", "xu": "ぅ", "xe": "ぇ", "xo": "ぉ",
+	"la": "ぁ", "li": "ぃ", "lu": "ぅ", "le": "ぇ", "lo": "ぉ",
+	"xya": "ゃ", "xyu": "ゅ", "xyo": "ょ", "lya": "ゃ", "lyu": "ゅ", "lyo": "ょ",
+	"xtu": "っ", "xtsu": "っ", "ltu": "っ", "ltsu": "っ",
+	"xwa": "ゎ", "lwa": "ゎ", "xka": "ゕ", "lka": "ゕ", "xke": "ゖ", "lke": "ゖ",
+	"xn": "ん",
+
+	"-": "ー",
+}
+
+// 带长音符号的元音先展开再转换：平假名写成两个元音（ō 按惯例为 ou），片假名用 ー
+var longVowels = [...]*strings.Replacer{
+	Hiragana: strings.NewReplacer(
+		"ā", "aa", "ī", "ii", "ū", "uu", "ē", "ee", "ō", "ou",
+		"â", "aa", "î", "ii", "û", "uu", "ê", "ee", "ô", "ou",
+		"Ā", "AA", "Ī", "II", "Ū", "UU", "Ē", "EE", "Ō", "OU",
+		"Â", "AA", "Î", "II", "Û", "UU", "Ê", "EE", "Ô", "OU",
+	),
+	Katakana: strings.NewReplacer(
+		"ā", "a-", "ī", "i-", "ū", "u-", "ē", "e-", "ō", "o-",
+		"â", "a-", "î", "i-", "û", "u-", "ê", "e-", "ô", "o-",
+		"Ā", "A-", "Ī", "I-", "Ū", "U-", "Ē", "E-", "Ō", "O-",
+		"Â", "A-", "Î", "I-", "Û", "U-", "Ê", "E-", "Ô", "O-",
+	),
+}
+
+const maxKeyLen = 4
+
+// prefixes 所有罗马音的前缀，用于判断输入到一半的罗马音
+var prefixes = func() map[string]bool {
+	res := make(map[string]bool)
+	for k := range romajiTable {
+		for i := 1; i < len(k); i++ {
+			res[k[:i]] = true
+		}
+	}
+	return res
+}()
+
+// ToKana 把 s 中的罗马音全部转换为假名，无法转换的字符原样保留
+func ToKana(s string, script Script) string {
+	res, _ := convert(s, script, true)
+	return res
+}
+
+// convert 转换 s 中的罗马音。final 为 false 时（边输入边转换），末尾还可能组成
+// 更长罗马音的部分（如 "k"、"sh"、"n"）保持原样，pending 为保留的部分
+func convert(s string, script Script, final bool) (string, string) {
+	if script == Off {
+		return s, ""
+	}
+	s = longVowels[script].Replace(s)
+	lower := asciiLower(s)
+	var b strings.Builder
+	emit := func(hira string) {
+		if script == Katakana {
+			hira = ToKatakana(hira)
+		}
+		b.WriteString(hira)
+	}
+
+	i := 0
+	for i < len(lower) {
+		c := lower[i]
+		rest := lower[i:]
+
+		// 假名等非 ASCII 字符原样保留
+		if c >= utf8.RuneSelf {
+			_, size := utf8.DecodeRuneInString(rest)
+			b.WriteString(s[i : i+size])
+			i += size
+			continue
+		}
+
+		var next byte
+		if len(rest) > 1 {
+			next = rest[1]
+		}
+
+		switch {
+		// 拨音：n' 明确表示 ん
+		case c == 'n' && next == '\'':
+			emit("ん")
+			i += 2
+			continue
+		// n 后面是辅音（不含 y）时为 ん；nn 后面不是元音和 y 时两个 n 合成一个 ん
+		case c == 'n' && next != 0 && !isVowel(next) && next != 'y':
+			if next == 'n' && len(rest) == 2 && !final {
+				// 只输入了 "nn"，还不知道后面接不接元音，先保留
+				b.WriteString(s[i:])
+				return b.String(), s[i:]
+			}
+			emit("ん")
+			if next == 'n' && (len(rest) < 3 || (!isVowel(rest[2]) && rest[2] != 'y')) {
+				i += 2
+			} else {
+				i++
+			}
+			continue
+		case c == 'n' && next == 0:
+			if final {
+				emit("ん")
+				i++
+				continue
+			}
+			b.WriteString(s[i:])
+			return b.String(), s[i:]
+		// 促音：双写辅音，或 tch；先于下面的 m 判断，mm 是 っm
+		case isConsonant(c) && (next == c || (c == 't' && strings.HasPrefix(rest, "tch"))):
+			emit("っ")
+			i++
+			continue
+		// 平文式在 b、p 前把 ん 写成 m，如 shimbun
+		case c == 'm' && (next == 'b' || next == 'p'):
+			emit("ん")
+			i++
+			continue
+		}
+
+		// 最长匹配
+		matched := false
+		for l := min(maxKeyLen, len(rest)); l > 0; l-- {
+			if kana, ok := romajiTable[rest[:l]]; ok {
+				emit(kana)
+				i += l
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if !final && prefixes[rest] {
+			b.WriteString(s[i:])
+			return b.String(), s[i:]
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String(), ""
+}
+
+// asciiLower 只把 ASCII 字母转成小写，保证与原字符串按字节对应
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aiueo", c) >= 0
+}
+
+func isConsonant(c byte) bool {
+	return c >= 'a' && c <= 'z' && !isVowel(c) && c != 'n'
+}
+
+// ToKatakana 平假名转片假名，其它字符不变
+func ToKatakana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ぁ' && r <= 'ゖ' {
+			return r + 0x60
+		}
+		return r
+	}, s)
+}
+
+// ToHiragana 片假名转平假名，其它字符不变
+func ToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - 0x60
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,72 @@
+package romaji
+
+import "testing"
+
+func TestToKana(t *testing.T) {
+	tests := []struct {
+		in     string
+		script Script
+		want   string
+	}{
+		{"gakkou", Hiragana, "がっこう"},
+		{"matcha", Hiragana, "まっちゃ"},
+		{"mma", Hiragana, "っま"},
+		{"shimbun", Hiragana, "しんぶん"},
+		{"shinbun", Hiragana, "しんぶん"},
+		{"sampo", Hiragana, "さんぽ"},
+		{"kon'ya", Hiragana, "こんや"},
+		{"konnyaku", Hiragana, "こんにゃく"},
+		{"konnichiha", Hiragana, "こんにちは"},
+		{"kinen", Hiragana, "きねん"},
+		{"kin'en", Hiragana, "きんえん"},
+		{"hon", Hiragana, "ほん"},
+		{"si", Hiragana, "し"},
+		{"shi", Hiragana, "し"},
+		{"tya", Hiragana, "ちゃ"},
+		{"tsu", Hiragana, "つ"},
+		{"xtu", Hiragana, "っ"},
+		{"la", Hiragana, "ぁ"},
+		{"tōkyō", Hiragana, "とうきょう"},
+		{"kōhī", Katakana, "コーヒー"},
+		{"ko-hi-", Katakana, "コーヒー"},
+		{"thi", Katakana, "ティ"},
+		{"Sushi", Hiragana, "すし"},
+		{"かna", Hiragana, "かな"},
+		{"abc", Off, "abc"},
+	}
+	for _, tt := range tests {
+		if got := ToKana(tt.in, tt.script); got != tt.want {
+			t.Errorf("ToKana(%q, %d) = %q, want %q", tt.in, tt.script, got, tt.want)
+		}
+	}
+}
+
+// 边输入边转换时，还可能组成更长罗马音的部分先保留
+func TestConvertPending(t *testing.T) {
+	tests := []struct {
+		in, want, pending string
+	}{
+		{"k", "k", "k"},
+		{"kas", "かs", "s"},
+		{"kan", "かn", "n"},
+		{"kann", "かnn", "nn"},
+		{"kanna", "かんな", ""},
+		{"kanj", "かんj", "j"},
+		{"sh", "sh", "sh"},
+	}
+	for _, tt := range tests {
+		got, pending := convert(tt.in, Hiragana, false)
+		if got != tt.want || pending != tt.pending {
+			t.Errorf("convert(%q) = %q, %q, want %q, %q", tt.in, got, pending, tt.want, tt.pending)
+		}
+	}
+}
+
+func TestKatakanaHiragana(t *testing.T) {
+	if got := ToKatakana("ひらがな ゔ"); got != "ヒラガナ ヴ" {
+		t.Errorf("ToKatakana = %q", got)
+	}
+	if got := ToHiragana("カタカナー"); got != "かたかなー" {
+		t.Errorf("ToHiragana = %q", got)
+	}
+}
@@ -0,0 +1,306 @@
+package romaji
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ==================================================
+// 假名 => 罗马音：把整个假名串（单词、短语）转写成平文式、训令式或日本式，
+// 处理拗音、促音 っ、长音 ー、ん 和助词 は/へ/を；
+// Match 判断作答的罗马音是否为假名的某种合理写法
+// ==================================================
+
+// System 罗马字拼写方式
+type System int
+
+const (
+	Hepburn    System = iota // 平文式
+	Kunrei                   // 训令式
+	NihonShiki               // 日本式
+)
+
+// Systems 全部拼写方式，按显示顺序
+var Systems = []System{Hepburn, Kunrei, NihonShiki}
+
+func (s System) String() string {
+	switch s {
+	case Kunrei:
+		return "训令式"
+	case NihonShiki:
+		return "日本式"
+	}
+	return "平文式"
+}
+
+// kanaRomaji 平假名（含拗音和外来语组合）的写法，依次为平文式、训令式、日本式，
+// 后面的写法与前一个相同时省略
+var kanaRomaji = map[string][]string{
+	"あ": {"a"}, "い": {"i"}, "う": {"u"}, "え": {"e"}, "お": {"o"},
+	"か": {"ka"}, "き": {"ki"}, "く": {"ku"}, "け": {"ke"}, "こ": {"ko"},
+	"が": {"ga"}, "ぎ": {"gi"}, "ぐ": {"gu"}, "げ": {"ge"}, "ご": {"go"},
+	"さ": {"sa"}, "し": {"shi", "si"}, "す": {"su"}, "せ": {"se"}, "そ": {"so"},
+	"ざ": {"za"}, "じ": {"ji", "zi"}, "ず": {"zu"}, "ぜ": {"ze"}, "ぞ": {"zo"},
+	"た": {"ta"}, "ち": {"chi", "ti"}, "つ": {"tsu", "tu"}, "て": {"te"}, "と": {"to"},
+	"だ": {"da"}, "ぢ": {"ji", "zi", "di"}, "づ": {"zu", "zu", "du"}, "で": {"de"}, "ど": {"do"},
+	"な": {"na"}, "に": {"ni"}, "ぬ": {"nu"}, "ね": {"ne"}, "の": {"no"},
+	"は": {"ha"}, "ひ": {"hi"}, "ふ": {"fu", "hu"}, "へ": {"he"}, "ほ": {"ho"},
+	"ば": {"ba"}, "び": {"bi"}, "ぶ": {"bu"}, "べ": {"be"}, "ぼ": {"bo"},
+	"ぱ": {"pa"}, "ぴ": {"pi"}, "ぷ": {"pu"}, "ぺ": {"pe"}, "ぽ": {"po"},
+	"ま": {"ma"}, "み": {"mi"}, "む": {"mu"}, "め": {"me"}, "も": {"mo"},
+	"や": {"ya"}, "ゆ": {"yu"}, "よ": {"yo"},
+	"ら": {"ra"}, "り": {"ri"}, "る": {"ru"}, "れ": {"re"}, "ろ": {"ro"},
+	"わ": {"wa"}, "ゐ": {"i", "i", "wi"}, "ゑ": {"e", "e", "we"}, "を": {"o", "o", "wo"},
+	"ゔ": {"vu"},
+
+	"きゃ": {"kya"}, "きゅ": {"kyu"}, "きょ": {"kyo"},
+	"ぎゃ": {"gya"}, "ぎゅ": {"gyu"}, "ぎょ": {"gyo"},
+	"しゃ": {"sha", "sya"}, "しゅ": {"shu", "syu"}, "しょ": {"sho", "syo"},
+	"じゃ": {"ja", "zya"}, "じゅ": {"ju", "zyu"}, "じょ": {"jo", "zyo"},
+	"ちゃ": {"cha", "tya"}, "ちゅ": {"chu", "tyu"}, "ちょ": {"cho", "tyo"},
+	"ぢゃ": {"ja", "zya", "dya"}, "ぢゅ": {"ju", "zyu", "dyu"}, "ぢょ": {"jo", "zyo", "dyo"},
+	"にゃ": {"nya"}, "にゅ": {"nyu"}, "にょ": {"nyo"},
+	"ひゃ": {"hya"}, "ひゅ": {"hyu"}, "ひょ": {"hyo"},
+	"びゃ": {"bya"}, "びゅ": {"byu"}, "びょ": {"byo"},
+	"ぴゃ": {"pya"}, "ぴゅ": {"pyu"}, "ぴょ": {"pyo"},
+	"みゃ": {"mya"}, "みゅ": {"myu"}, "みょ": {"myo"},
+	"りゃ": {"rya"}, "りゅ": {"ryu"}, "りょ": {"ryo"},
+	"くゎ": {"ka", "ka", "kwa"}, "ぐゎ": {"ga", "ga", "gwa"},
+
+	// 外来语
+	"ふぁ": {"fa"}, "ふぃ": {"fi"}, "ふぇ": {"fe"}, "ふぉ": {"fo"}, "ふゅ": {"fyu"},
+	"てぃ": {"ti"}, "でぃ": {"di"}, "とぅ": {"tu"}, "どぅ": {"du"}, "てゅ": {"tyu"}, "でゅ": {"dyu"},
+	"うぃ": {"wi"}, "うぇ": {"we"}, "うぉ": {"wo"}, "いぇ": {"ye"},
+	"しぇ": {"she", "sye"}, "ちぇ": {"che", "tye"}, "じぇ": {"je", "zye"},
+	"つぁ": {"tsa"}, "つぃ": {"tsi"}, "つぇ": {"tse"}, "つぉ": {"tso"},
+	"ゔぁ": {"va"}, "ゔぃ": {"vi"}, "ゔぇ": {"ve"}, "ゔぉ": {"vo"},
+	"くぁ": {"kwa"}, "ぐぁ": {"gwa"},
+
+	// 单独出现的小写假名
+	"ぁ": {"a"}, "ぃ": {"i"}, "ぅ": {"u"}, "ぇ": {"e"}, "ぉ": {"o"},
+	"ゃ": {"ya"}, "ゅ": {"yu"}, "ょ": {"yo"}, "ゎ": {"wa"},
+}
+
+// 带长音符号的元音：平文式用 ā，训令式和日本式用 â
+var macrons = map[byte][2]string{
+	'a': {"ā", "â"}, 'i': {"ī", "î"}, 'u': {"ū", "û"}, 'e': {"ē", "ê"}, 'o': {"ō", "ô"},
+}
+
+var punctuation = strings.NewReplacer("、", ", ", "。", ". ", "・", " ", "～", "~", "〜", "~", "　", " ", "！", "!", "？", "?")
+
+// form 假名在 sys 下的写法
+func form(kana string, sys System) string {
+	f := kanaRomaji[kana]
+	if len(f) == 0 {
+		return kana
+	}
+	return f[min(int(sys), len(f)-1)]
+}
+
+// splitKana 把平假名串切成音节：拗音和外来语组合算一个音节，其它字符各算一个
+func splitKana(s string) []string {
+	rs := []rune(s)
+	var res []string
+	for i := 0; i < len(rs); i++ {
+		if i+1 < len(rs) {
+			if _, ok := kanaRomaji[string(rs[i:i+2])]; ok {
+				res = append(res, string(rs[i:i+2]))
+				i++
+				continue
+			}
+		}
+		res = append(res, string(rs[i]))
+	}
+	return res
+}
+
+// isParticle は/へ 是否按助词读作 wa/e：在词尾且前面至少有两个假名（如 こんにちは），
+// 或者单独成词
+func isParticle(units []string, i int) bool {
+	if i+1 < len(units) && isKanaUnit(units[i+1]) {
+		return false
+	}
+	before := 0
+	for j := i - 1; j >= 0 && isKanaUnit(units[j]); j-- {
+		before++
+	}
+	return before == 0 && i > 0 || before >= 2
+}
+
+func isKanaUnit(u string) bool {
+	if _, ok := kanaRomaji[u]; ok {
+		return true
+	}
+	return u == "っ" || u == "ん" || u == "ー"
+}
+
+// ToRomaji 把假名串转写为罗马音，平假名和片假名都可以，其它字符原样保留。
+// 平假名的长音按字面写（おう => ou），片假名的 ー 写成长音符号
+func ToRomaji(kana string, sys System) string {
+	units := splitKana(ToHiragana(kana))
+	var b strings.Builder
+	for i, u := range units {
+		var next string
+		if i+1 < len(units) {
+			if _, ok := kanaRomaji[units[i+1]]; ok {
+				next = form(units[i+1], sys)
+			}
+		}
+
+		switch {
+		case u == "っ":
+			switch {
+			case strings.HasPrefix(next, "ch") && sys == Hepburn:
+				b.WriteByte('t')
+			case next != "" && !isVowel(next[0]):
+				b.WriteByte(next[0])
+			default:
+				b.WriteByte('\'') // 词尾或元音前的促音
+			}
+		case u == "ん":
+			switch {
+			// 平文式在 b、m、p 前写成 m，如 shimbun、sampo
+			case sys == Hepburn && next != "" && strings.IndexByte("bmp", next[0]) >= 0:
+				b.WriteByte('m')
+			case next != "" && (isVowel(next[0]) || next[0] == 'y'):
+				b.WriteString("n'")
+			default:
+				b.WriteByte('n')
+			}
+		case u == "ー":
+			out := b.String()
+			if out != "" {
+				if m, ok := macrons[out[len(out)-1]]; ok {
+					b.Reset()
+					b.WriteString(out[:len(out)-1])
+					b.WriteString(m[min(int(sys), 1)])
+					continue
+				}
+			}
+			b.WriteByte('-')
+		case (u == "は" || u == "へ") && isParticle(units, i):
+			if u == "は" {
+				b.WriteString("wa")
+			} else {
+				b.WriteString("e")
+			}
+		default:
+			if _, ok := kanaRomaji[u]; ok {
+				b.WriteString(form(u, sys))
+			} else {
+				b.WriteString(punctuation.Replace(u))
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Match 判断 answer 是否是 kana 的一种罗马音写法：平文式、训令式、日本式都可以，
+// 长音可以写成 ō、ô、ou、oo 或 -，助词 は/へ/を 可以按读音或按字面写，
+// 不区分大小写，忽略空格和 ' 以外的标点
+func Match(answer, kana string) bool {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return false
+	}
+	if canonical(ToKana(answer, Hiragana)) == canonical(kana) {
+		return true
+	}
+	// 外来语的 ti、di、tu 等按输入法会转换成别的假名，直接与各种写法比较
+	a := plainRomaji(answer)
+	for _, sys := range Systems {
+		if a == plainRomaji(ToRomaji(kana, sys)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRomaji 判断 s 是否全部由罗马字母（含长音符号）、空格和标点组成，不含假名和汉字
+func IsRomaji(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+	return strings.TrimSpace(s) != ""
+}
+
+// plainRomaji 用于直接比较罗马音：小写，长音符号和 - 写成重复的元音，只保留字母和
+// 区分 ん 的 '（kin'en 是 きんえん，kinen 是 きねん）
+func plainRomaji(s string) string {
+	s = longVowels[Hiragana].Replace(strings.ToLower(s))
+	var b strings.Builder
+	var last rune
+	for _, r := range s {
+		switch {
+		case r == '-' && last < 0x80 && isVowel(byte(last)):
+			r = last
+		case r == '’':
+			r = '\''
+		case r != '\'' && !unicode.IsLetter(r):
+			continue
+		}
+		b.WriteRune(r)
+		last = r
+	}
+	return b.String()
+}
+
+// canonical 把假名串规范化以便比较：统一为平假名，长音统一写成重复的元音
+// （おう、おお、ー 都变成 おお，えい 变成 ええ），ぢ/づ 当作 じ/ず，
+// 助词 は/へ/を 当作 わ/え/お，去掉词尾的促音和非文字字符
+func canonical(kana string) string {
+	units := splitKana(ToHiragana(kana))
+	var res []rune
+	lastVowel := func() byte {
+		if len(res) == 0 {
+			return 0
+		}
+		return vowelOf(res[len(res)-1])
+	}
+	for i, u := range units {
+		switch {
+		case u == "ー":
+			if v := lastVowel(); v != 0 {
+				res = append(res, vowelKana[v])
+			}
+			continue
+		case u == "う" && lastVowel() == 'o', u == "い" && lastVowel() == 'e':
+			res = append(res, vowelKana[lastVowel()])
+			continue
+		case u == "っ" && (i+1 == len(units) || !isKanaUnit(units[i+1])):
+			continue
+		case (u == "は" || u == "へ") && isParticle(units, i):
+			u = map[string]string{"は": "わ", "へ": "え"}[u]
+		case u == "を":
+			u = "お"
+		}
+		for _, r := range u {
+			switch {
+			case r == 'ぢ':
+				r = 'じ'
+			case r == 'づ':
+				r = 'ず'
+			case !unicode.IsLetter(r):
+				continue
+			}
+			res = append(res, r)
+		}
+	}
+	return string(res)
+}
+
+var vowelKana = map[byte]rune{'a': 'あ', 'i': 'い', 'u': 'う', 'e': 'え', 'o': 'お'}
+
+// vowelOf 平假名的元音，不是假名或没有元音时返回 0
+func vowelOf(r rune) byte {
+	f := kanaRomaji[string(r)]
+	if len(f) == 0 {
+		return 0
+	}
+	if v := f[0][len(f[0])-1]; isVowel(v) {
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package romaji
+
+import "testing"
+
+func TestToRomaji(t *testing.T) {
+	tests := []struct {
+		kana                      string
+		hepburn, kunrei, nihonShi string
+	}{
+		{"がっこう", "gakkou", "gakkou", "gakkou"},
+		{"まっちゃ", "matcha", "mattya", "mattya"},
+		{"しんぶん", "shimbun", "sinbun", "sinbun"},
+		{"さんぽ", "sampo", "sanpo", "sanpo"},
+		{"さんま", "samma", "sanma", "sanma"},
+		{"きんえん", "kin'en", "kin'en", "kin'en"},
+		{"きねん", "kinen", "kinen", "kinen"},
+		{"こんや", "kon'ya", "kon'ya", "kon'ya"},
+		{"ほん", "hon", "hon", "hon"},
+		{"ちず", "chizu", "tizu", "tizu"},
+		{"はなぢ", "hanaji", "hanazi", "hanadi"},
+		{"つづく", "tsuzuku", "tuzuku", "tuduku"},
+		{"しゃしん", "shashin", "syasin", "syasin"},
+		{"コーヒー", "kōhī", "kôhî", "kôhî"},
+		{"こんにちは", "konnichiwa", "konnitiwa", "konnitiwa"},
+		{"は", "ha", "ha", "ha"},
+		{"を", "o", "o", "wo"},
+		{"ティー", "tī", "tî", "tî"},
+		{"あっ", "a'", "a'", "a'"},
+		{"すし、さしみ", "sushi, sashimi", "susi, sasimi", "susi, sasimi"},
+	}
+	for _, tt := range tests {
+		for sys, want := range []string{tt.hepburn, tt.kunrei, tt.nihonShi} {
+			if got := ToRomaji(tt.kana, System(sys)); got != want {
+				t.Errorf("ToRomaji(%q, %s) = %q, want %q", tt.kana, System(sys), got, want)
+			}
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		answer, kana string
+		want         bool
+	}{
+		{"gakkou", "がっこう", true},
+		{"gakko", "がっこう", false},
+		{"gakkō", "がっこう", true},
+		{"GAKKOU", "がっこう", true},
+		{"shimbun", "しんぶん", true},
+		{"shinbun", "しんぶん", true},
+		{"sinbun", "しんぶん", true},
+		{"samma", "さんま", true},
+		{"sanma", "さんま", true},
+		{"kin'en", "きんえん", true},
+		{"kin’en", "きんえん", true},
+		{"kinen", "きんえん", false},
+		{"kinen", "きねん", true},
+		{"kin'en", "きねん", false},
+		{"kon'ya", "こんや", true},
+		{"konya", "こんや", false},
+		{"tizu", "ちず", true},
+		{"kōhī", "コーヒー", true},
+		{"kouhii", "コーヒー", true},
+		{"ko-hi-", "コーヒー", true},
+		{"konnichiwa", "こんにちは", true},
+		{"konnichiha", "こんにちは", true},
+		{"ti-", "ティー", true},
+		{"chotto", "ちょっと", true},
+		{"tyotto", "ちょっと", true},
+		{"choto", "ちょっと", false},
+		{"", "あ", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.answer, tt.kana); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.answer, tt.kana, got, tt.want)
+		}
+	}
+}
+
+func TestIsRomaji(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"gakkou", true},
+		{"kōhī", true},
+		{"kin'en", true},
+		{"がっこう", false},
+		{"学校", false},
+		{" ", false},
+	}
+	for _, tt := range tests {
+		if got := IsRomaji(tt.s); got != tt.want {
+			t.Errorf("IsRomaji(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ==================================================
+// 练习的题量：不限、固定题数，或者每个项目各练一次。
+// 两个模块的练习窗口共用，题目做完后显示总结（见 summary.go）
+// ==================================================
+
+// Length 题量，正数为题数
+type Length int
+
+const (
+	Endless  Length = 0  // 不限题量
+	EachOnce Length = -1 // 每个项目各一次
+)
+
+// Lengths 可选的题量
+var Lengths = []Length{Endless, 10, 20, 50, EachOnce}
+
+func (l Length) String() string {
+	switch l {
+	case Endless:
+		return "不限题量"
+	case EachOnce:
+		return "每个各一次"
+	}
+	return fmt.Sprintf("%d 题", int(l))
+}
+
+const lengthPrefKey = "session.length"
+
+// Load 读取上次选择的题量，默认不限
+func Load(a fyne.App) Length {
+	return Length(a.Preferences().IntWithFallback(lengthPrefKey, int(Endless)))
+}
+
+// NewLengthSelect 选择题量的下拉框，修改后立即保存
+func NewLengthSelect(a fyne.App) *widget.Select {
+	var options []string
+	for _, l := range Lengths {
+		options = append(options, "题量: "+l.String())
+	}
+	sel := widget.NewSelect(options, nil)
+	for i, l := range Lengths {
+		if l == Load(a) {
+			sel.SetSelectedIndex(i)
+		}
+	}
+	sel.OnChanged = func(string) {
+		if i := sel.SelectedIndex(); i >= 0 {
+			a.Preferences().SetInt(lengthPrefKey, int(Lengths[i]))
+		}
+	}
+	return sel
+}
+
+// Mistake 一道答错的题
+type Mistake struct {
+	Item     string // 项目的标识，重练错题时使用
+	Question string
+	Answer   string // 学习者的作答
+	Expected string
+}
+
+// Session 一次练习的进度和结果
+type Session struct {
+	length   Length
+	size     int // 项目总数
+	done     map[string]bool
+	total    int // 判过的题数
+	correct  int
+	start    time.Time
+	mistakes []Mistake
+}
+
+// New 开始一次练习，size 为可出题的项目总数
+func New(length Length, size int) *Session {
+	return &Session{
+		length: length,
+		size:   size,
+		done:   make(map[string]bool),
+		start:  time.Now(),
+	}
+}
+
+// Pending 还可以出题的项目：每个各一次时去掉已经做过的，否则原样返回
+func (s *Session) Pending(items []string) []string {
+	if s.length != EachOnce {
+		return items
+	}
+	var res []string
+	for _, it := range items {
+		if !s.done[it] {
+			res = append(res, it)
+		}
+	}
+	if len(res) == 0 {
+		return items
+	}
+	return res
+}
+
+// Add 记录一次判题，答错时记下 m（Item 由 item 填写）
+func (s *Session) Add(item string, correct bool, m Mistake) {
+	s.done[item] = true
+	s.total++
+	if correct {
+		s.correct++
+		return
+	}
+	m.Item = item
+	s.mistakes = append(s.mistakes, m)
+}
+
+// Finished 题目是否已经做完；不限题量时永远不会结束
+func (s *Session) Finished() bool {
+	switch {
+	case s.length == Endless:
+		return false
+	case s.length == EachOnce:
+		return len(s.done) >= s.size
+	}
+	return s.total >= int(s.length)
+}
+
+// Progress 进度，如 "第 3/10 题"；不限题量时为已做的题数
+func (s *Session) Progress() string {
+	n := s.total + 1
+	switch s.length {
+	case Endless:
+		return fmt.Sprintf("第 %d 题", n)
+	case EachOnce:
+		return fmt.Sprintf("第 %d/%d 题", min(len(s.done)+1, s.size), s.size)
+	}
+	return fmt.Sprintf("第 %d/%d 题", n, int(s.length))
+}
+
+// Missed 答错过的项目，按第一次答错的顺序，不重复
+func (s *Session) Missed() []string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, m := range s.mistakes {
+		if !seen[m.Item] {
+			seen[m.Item] = true
+			res = append(res, m.Item)
+		}
+	}
+	return res
+}
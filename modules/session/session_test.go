@@ -0,0 +1,99 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+// answer 依次作答，items 中以 "!" 开头的表示答错
+func answer(s *Session, items ...string) {
+	for _, it := range items {
+		if it[0] == '!' {
+			s.Add(it[1:], false, Mistake{Question: it[1:]})
+		} else {
+			s.Add(it, true, Mistake{})
+		}
+	}
+}
+
+func TestPending(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	tests := []struct {
+		length Length
+		done   []string
+		want   []string
+	}{
+		{Endless, []string{"a", "b"}, items},
+		{10, []string{"a", "b"}, items},
+		{EachOnce, nil, items},
+		{EachOnce, []string{"a", "!c"}, []string{"b"}},
+		{EachOnce, []string{"a", "a"}, []string{"b", "c"}},
+		{EachOnce, []string{"a", "b", "c"}, items}, // 都做过后不再过滤，避免没有题可出
+	}
+	for _, tt := range tests {
+		s := New(tt.length, len(items))
+		answer(s, tt.done...)
+		if got := s.Pending(items); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v after %v: Pending = %v, want %v", tt.length, tt.done, got, tt.want)
+		}
+	}
+}
+
+func TestFinishedAndProgress(t *testing.T) {
+	tests := []struct {
+		length   Length
+		size     int
+		done     []string
+		finished bool
+		progress string // 为空时不检查（练习结束后不再显示进度）
+	}{
+		{Endless, 3, nil, false, "第 1 题"},
+		{Endless, 3, []string{"a", "b", "c", "a"}, false, "第 5 题"},
+		{10, 3, []string{"a", "!b"}, false, "第 3/10 题"},
+		{10, 3, []string{"a", "b", "c", "a", "b", "c", "a", "b", "c"}, false, "第 10/10 题"},
+		{10, 3, []string{"a", "b", "c", "a", "b", "c", "a", "b", "c", "!a"}, true, ""},
+		{EachOnce, 3, nil, false, "第 1/3 题"},
+		{EachOnce, 3, []string{"a", "a", "!a"}, false, "第 2/3 题"},
+		{EachOnce, 3, []string{"a", "!b", "c"}, true, "第 3/3 题"},
+	}
+	for _, tt := range tests {
+		s := New(tt.length, tt.size)
+		answer(s, tt.done...)
+		if got := s.Finished(); got != tt.finished {
+			t.Errorf("%v after %v: Finished = %v, want %v", tt.length, tt.done, got, tt.finished)
+		}
+		if got := s.Progress(); tt.progress != "" && got != tt.progress {
+			t.Errorf("%v after %v: Progress = %q, want %q", tt.length, tt.done, got, tt.progress)
+		}
+	}
+}
+
+func TestMissed(t *testing.T) {
+	s := New(Endless, 4)
+	answer(s, "!b", "a", "!c", "!b", "d", "!a")
+	if got, want := s.Missed(), []string{"b", "c", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Missed = %v, want %v", got, want)
+	}
+	if s.mistakes[0].Item != "b" || s.mistakes[0].Question != "b" {
+		t.Errorf("mistake = %+v, want Item filled in", s.mistakes[0])
+	}
+	if s.total != 6 || s.correct != 2 {
+		t.Errorf("total %d correct %d, want 6 2", s.total, s.correct)
+	}
+}
+
+func TestLengthString(t *testing.T) {
+	tests := []struct {
+		l    Length
+		want string
+	}{
+		{Endless, "不限题量"},
+		{EachOnce, "每个各一次"},
+		{20, "20 题"},
+	}
+	for _, tt := range tests {
+		if got := tt.l.String(); got != tt.want {
+			t.Errorf("Length(%d).String() = %q, want %q", int(tt.l), got, tt.want)
+		}
+	}
+}
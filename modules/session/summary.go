@@ -0,0 +1,68 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ShowSummary 把窗口内容换成本次练习的总结：得分、用时、所有错题；
+// redrill 不为 nil 且有错题时显示"只练错题"按钮，参数为答错过的项目
+func ShowSummary(w fyne.Window, s *Session, redrill func(items []string)) {
+	elapsed := time.Since(s.start).Round(time.Second)
+	lines := []fyne.CanvasObject{widget.NewLabel("本次练习结束")}
+	if s.total > 0 {
+		lines = append(lines, widget.NewLabel(fmt.Sprintf("得分: %d/%d (%.0f%%)",
+			s.correct, s.total, float64(s.correct)/float64(s.total)*100)))
+	}
+	lines = append(lines, widget.NewLabel("用时: "+formatDuration(elapsed)))
+
+	mistakes := widget.NewList(
+		func() int { return len(s.mistakes) },
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			m := s.mistakes[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s\n你的答案: %s    正确答案: %s", m.Question, m.Answer, m.Expected))
+		},
+	)
+	if len(s.mistakes) == 0 {
+		if s.total > 0 {
+			lines = append(lines, widget.NewLabel("全部答对！"))
+		}
+	} else {
+		lines = append(lines, widget.NewLabel(fmt.Sprintf("错题 (%d)：", len(s.mistakes))))
+	}
+
+	var buttons []fyne.CanvasObject
+	if redrill != nil && len(s.mistakes) > 0 {
+		buttons = append(buttons, widget.NewButton("只练错题", func() {
+			redrill(s.Missed())
+		}))
+	}
+	buttons = append(buttons, widget.NewButton("关闭", func() {
+		w.Close()
+	}))
+
+	w.SetContent(container.NewBorder(
+		container.NewVBox(lines...),
+		container.NewHBox(buttons...),
+		nil, nil,
+		mistakes,
+	))
+	w.Resize(w.Canvas().Size().Max(fyne.NewSize(480, 420)))
+}
+
+// formatDuration 如 "2 分 15 秒"
+func formatDuration(d time.Duration) string {
+	m := int(d.Minutes())
+	sec := int(d.Seconds()) % 60
+	if m == 0 {
+		return fmt.Sprintf("%d 秒", sec)
+	}
+	return fmt.Sprintf("%d 分 %d 秒", m, sec)
+}
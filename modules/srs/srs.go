@@ -0,0 +1,201 @@
+package srs
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"FiftySound/modules/appdata"
+)
+
+// ==================================================
+// 间隔重复调度 (SM-2)
+// 五十音和单词模块共用，每个模块一个 Scheduler，状态保存在应用存储中
+// ==================================================
+
+// Grade 表示一次复习的自评结果
+type Grade int
+
+const (
+	Again Grade = iota // 忘记了
+	Hard               // 想起来但很吃力
+	Good               // 正常想起
+	Easy               // 很轻松
+)
+
+const (
+	defaultEase  = 2.5
+	minEase      = 1.3
+	relearnDelay = time.Minute // 答错后在本次练习中多久后再次出现
+	day          = 24 * time.Hour
+)
+
+// Card 记录单个题目（假名或单词）的复习状态
+type Card struct {
+	Ease        float64   `json:"ease"`
+	Interval    float64   `json:"interval"` // 单位: 天
+	Repetitions int       `json:"repetitions"`
+	Lapses      int       `json:"lapses"`
+	Due         time.Time `json:"due"`
+	LastReview  time.Time `json:"lastReview"`
+}
+
+// Scheduler 管理一组题目的复习状态，并负责选出下一题
+type Scheduler struct {
+	mu    sync.Mutex
+	app   fyne.App
+	file  string
+	cards map[string]*Card
+	shown map[string]int // 本次运行中各题最近一次被选出的序号，不保存
+	turn  int
+}
+
+// Load 从应用存储中读取名为 name 的调度数据，不存在时返回空的调度器
+func Load(a fyne.App, name string) (*Scheduler, error) {
+	s := &Scheduler{
+		app:   a,
+		file:  "srs_" + name + ".json",
+		cards: make(map[string]*Card),
+		shown: make(map[string]int),
+	}
+	if err := appdata.LoadJSON(a, s.file, &s.cards); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// Card 返回 key 的复习状态，ok 为 false 表示从未复习过
+func (s *Scheduler) Card(key string) (Card, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.cards[key]
+	if !ok {
+		return Card{}, false
+	}
+	return *c, true
+}
+
+// Review 记录一次复习结果，更新难度系数、间隔和到期时间，并立即保存
+func (s *Scheduler) Review(key string, g Grade) error {
+	s.mu.Lock()
+	now := time.Now()
+	c, ok := s.cards[key]
+	if !ok {
+		c = &Card{Ease: defaultEase}
+		s.cards[key] = c
+	}
+	review(c, g, now)
+	s.mu.Unlock()
+	return s.Save()
+}
+
+// ReviewResult 对于只有对错的判题，答对按 Good、答错按 Again 记录
+func (s *Scheduler) ReviewResult(key string, correct bool) error {
+	if correct {
+		return s.Review(key, Good)
+	}
+	return s.Review(key, Again)
+}
+
+func review(c *Card, g Grade, now time.Time) {
+	// SM-2 的回忆质量 q (0~5)
+	q := map[Grade]float64{Again: 1, Hard: 3, Good: 4, Easy: 5}[g]
+
+	c.Ease += 0.1 - (5-q)*(0.08+(5-q)*0.02)
+	c.Ease = math.Max(c.Ease, minEase)
+	c.LastReview = now
+
+	if g == Again {
+		c.Repetitions = 0
+		c.Lapses++
+		c.Interval = 0
+		c.Due = now.Add(relearnDelay)
+		return
+	}
+
+	switch c.Repetitions {
+	case 0:
+		c.Interval = 1
+	case 1:
+		c.Interval = 6
+	default:
+		c.Interval = c.Interval * c.Ease
+	}
+	if g == Hard {
+		c.Interval = math.Max(1, c.Interval*0.6)
+	}
+	if g == Easy {
+		c.Interval *= 1.3
+	}
+	c.Repetitions++
+	c.Due = now.Add(time.Duration(c.Interval * float64(day)))
+}
+
+// Save 把当前状态写入应用存储
+func (s *Scheduler) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return appdata.SaveJSON(s.app, s.file, s.cards)
+}
+
+// Next 从 keys 中选出下一题：
+//  1. 已到期的题目
+//  2. 从未复习过的新题，随机选
+//  3. 都没有时从还没到期的题目中选，保证可以一直练下去
+//
+// 1 和 3 中最久没出过的题目优先（没有判题就跳过的题目不会因此一直排在前面），
+// 同样久时最早到期的优先。last 为上一题，有其它选择时不会连续出现同一题
+func (s *Scheduler) Next(keys []string, last string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var due, fresh, later []string
+	for _, k := range keys {
+		if k == last && len(keys) > 1 {
+			continue
+		}
+		c, ok := s.cards[k]
+		switch {
+		case !ok:
+			fresh = append(fresh, k)
+		case !c.Due.After(now):
+			due = append(due, k)
+		default:
+			later = append(later, k)
+		}
+	}
+
+	pick := func(list []string) string {
+		sort.SliceStable(list, func(i, j int) bool {
+			a, b := list[i], list[j]
+			if s.shown[a] != s.shown[b] {
+				return s.shown[a] < s.shown[b]
+			}
+			return s.cards[a].Due.Before(s.cards[b].Due)
+		})
+		return list[0]
+	}
+
+	var k string
+	switch {
+	case len(due) > 0:
+		k = pick(due)
+	case len(fresh) > 0:
+		k = fresh[rand.Intn(len(fresh))]
+	case len(later) > 0:
+		k = pick(later)
+	default:
+		k = keys[0]
+	}
+	s.turn++
+	s.shown[k] = s.turn
+	return k
+}
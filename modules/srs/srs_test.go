@@ -0,0 +1,113 @@
+package srs
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func newTestScheduler(cards map[string]*Card) *Scheduler {
+	return &Scheduler{cards: cards, shown: make(map[string]int)}
+}
+
+func TestReview(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		grades   []Grade
+		interval float64 // 天
+		reps     int
+		lapses   int
+		ease     float64
+	}{
+		{"first good", []Grade{Good}, 1, 1, 0, 2.5},
+		{"second good", []Grade{Good, Good}, 6, 2, 0, 2.5},
+		{"third good", []Grade{Good, Good, Good}, 15, 3, 0, 2.5},
+		{"easy", []Grade{Easy}, 1.3, 1, 0, 2.6},
+		{"hard", []Grade{Good, Hard}, 3.6, 2, 0, 2.36},
+		{"hard keeps at least a day", []Grade{Hard}, 1, 1, 0, 2.36},
+		{"again resets", []Grade{Good, Good, Again}, 0, 0, 1, 1.96},
+		{"relearn after again", []Grade{Good, Again, Good}, 1, 1, 1, 1.96},
+		{"ease floor", []Grade{Again, Again, Again, Again, Again}, 0, 0, 5, minEase},
+	}
+	for _, tt := range tests {
+		c := &Card{Ease: defaultEase}
+		for _, g := range tt.grades {
+			review(c, g, now)
+		}
+		if math.Abs(c.Interval-tt.interval) > 1e-9 || c.Repetitions != tt.reps || c.Lapses != tt.lapses ||
+			math.Abs(c.Ease-tt.ease) > 1e-9 {
+			t.Errorf("%s: interval %v reps %d lapses %d ease %v, want %v %d %d %v",
+				tt.name, c.Interval, c.Repetitions, c.Lapses, c.Ease, tt.interval, tt.reps, tt.lapses, tt.ease)
+		}
+		want := now.Add(time.Duration(tt.interval * float64(day)))
+		if tt.interval == 0 {
+			want = now.Add(relearnDelay)
+		}
+		if !c.Due.Equal(want) {
+			t.Errorf("%s: due %v, want %v", tt.name, c.Due, want)
+		}
+	}
+}
+
+func TestNextPriority(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler(map[string]*Card{
+		"due":   {Due: now.Add(-time.Hour)},
+		"later": {Due: now.Add(time.Hour)},
+	})
+	if got := s.Next([]string{"later", "new", "due"}, ""); got != "due" {
+		t.Errorf("Next = %q, want due first", got)
+	}
+	if got := s.Next([]string{"later", "new"}, ""); got != "new" {
+		t.Errorf("Next = %q, want new before later", got)
+	}
+	if got := s.Next([]string{"later"}, ""); got != "later" {
+		t.Errorf("Next = %q, want later when nothing else", got)
+	}
+	if got := s.Next([]string{"due", "later"}, "due"); got != "later" {
+		t.Errorf("Next = %q, want last skipped", got)
+	}
+	if got := s.Next([]string{"due"}, "due"); got != "due" {
+		t.Errorf("Next = %q, want last when it is the only key", got)
+	}
+	if got := s.Next(nil, ""); got != "" {
+		t.Errorf("Next(nil) = %q", got)
+	}
+}
+
+// 不判题一直点下一题时，到期和未到期的题目都要轮流出现，而不是在两题之间来回
+func TestNextRotates(t *testing.T) {
+	now := time.Now()
+	for _, offset := range []time.Duration{-time.Hour, time.Hour} {
+		cards := make(map[string]*Card)
+		keys := []string{"a", "b", "c", "d"}
+		for i, k := range keys {
+			cards[k] = &Card{Due: now.Add(offset + time.Duration(i)*time.Minute)}
+		}
+		s := newTestScheduler(cards)
+		seen := make(map[string]int)
+		last := ""
+		for i := 0; i < 2*len(keys); i++ {
+			last = s.Next(keys, last)
+			seen[last]++
+		}
+		for _, k := range keys {
+			if seen[k] != 2 {
+				t.Errorf("offset %v: %q shown %d times in %v", offset, k, seen[k], seen)
+			}
+		}
+	}
+}
+
+// 同样久没出过时最早到期的优先
+func TestNextEarliestDueFirst(t *testing.T) {
+	now := time.Now()
+	s := newTestScheduler(map[string]*Card{
+		"x": {Due: now.Add(-time.Minute)},
+		"y": {Due: now.Add(-time.Hour)},
+	})
+	if got := s.Next([]string{"x", "y"}, ""); got != "y" {
+		t.Errorf("Next = %q, want the most overdue", got)
+	}
+}
@@ -0,0 +1,78 @@
+package vocabulary
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"FiftySound/modules/appdata"
+)
+
+// ==================================================
+// 词库离线缓存：下载成功的 ZIP 连同 ETag/Last-Modified 保存在本地，
+// 启动时做条件请求，网络不可用时退回到缓存
+// ==================================================
+
+// cacheFiles 每个地址一组缓存文件，文件名取地址的哈希
+func cacheFiles(url string) (zipFile, metaFile string) {
+	sum := sha1.Sum([]byte(url))
+	name := "vocabulary_cache_" + hex.EncodeToString(sum[:6])
+	return name + ".zip", name + ".json"
+}
+
+type zipCacheMeta struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"lastModified"`
+	SavedAt      time.Time `json:"savedAt"`
+}
+
+// cachedZip 本地缓存的词库 ZIP
+type cachedZip struct {
+	meta zipCacheMeta
+	data []byte
+}
+
+// loadZipCache 读取 url 对应的缓存，没有缓存或缓存来自其它地址时返回 nil
+func loadZipCache(myApp fyne.App, url string) *cachedZip {
+	zipFile, metaFile := cacheFiles(url)
+	var meta zipCacheMeta
+	if err := appdata.LoadJSON(myApp, metaFile, &meta); err != nil {
+		fyne.LogError("读取词库缓存信息失败", err)
+		return nil
+	}
+	if meta.URL != url {
+		return nil
+	}
+	data, ok, err := appdata.ReadFile(myApp, zipFile)
+	if err != nil {
+		fyne.LogError("读取词库缓存失败", err)
+		return nil
+	}
+	if !ok || len(data) == 0 {
+		return nil
+	}
+	return &cachedZip{meta: meta, data: data}
+}
+
+func saveZipCache(myApp fyne.App, url string, data []byte, header http.Header) error {
+	zipFile, metaFile := cacheFiles(url)
+	if err := appdata.WriteFile(myApp, zipFile, data); err != nil {
+		return err
+	}
+	return appdata.SaveJSON(myApp, metaFile, zipCacheMeta{
+		URL:          url,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		SavedAt:      time.Now(),
+	})
+}
+
+// offlineNotice 使用缓存时在主页面显示的提示
+func (c *cachedZip) offlineNotice(name string) string {
+	return fmt.Sprintf("离线，%s 使用 %s 缓存的词库", name, c.meta.SavedAt.Local().Format("2006-01-02 15:04"))
+}
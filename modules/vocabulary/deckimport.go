@@ -0,0 +1,396 @@
+package vocabulary
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/anki"
+)
+
+// ==================================================
+// 导入 Anki 卡组 (.apkg) 和 CSV/TSV 表格：由用户把字段对应到假名、汉字、中文释义，
+// 结果作为导入的单元包出现在单元选择树中
+// ==================================================
+
+// fieldLayout 一种字段布局：Anki 的一个笔记类型，或一个表格文件
+type fieldLayout struct {
+	name   string
+	fields []string // Anki 笔记类型的字段名；表格文件的列名由 header 决定
+	rows   []fieldRow
+	table  bool // 表格文件：可以选择第一行是否为表头，可以指定单元列
+	header bool
+}
+
+type fieldRow struct {
+	unit   string // 所在单元（Anki 的牌组），为空时由单元列或包名决定
+	values []string
+}
+
+// fieldMapping WordItem 各字段对应的列下标，-1 表示不使用
+type fieldMapping struct {
+	kana, kanji, chines, unit int
+}
+
+// columns 供用户选择的列名
+func (l *fieldLayout) columns() []string {
+	if !l.table {
+		return l.fields
+	}
+	n := 0
+	for _, r := range l.rows {
+		n = max(n, len(r.values))
+	}
+	res := make([]string, n)
+	for i := range res {
+		res[i] = fmt.Sprintf("第%d列", i+1)
+		if l.header && len(l.rows) > 0 && i < len(l.rows[0].values) && l.rows[0].values[i] != "" {
+			res[i] = l.rows[0].values[i]
+		}
+	}
+	return res
+}
+
+// data 去掉表头后的数据行
+func (l *fieldLayout) data() []fieldRow {
+	if l.table && l.header && len(l.rows) > 0 {
+		return l.rows[1:]
+	}
+	return l.rows
+}
+
+// parseApkg 每个笔记类型是一种布局，笔记所在的牌组就是单元
+func parseApkg(data []byte) ([]*fieldLayout, error) {
+	pkg, err := anki.ReadPackage(data)
+	if err != nil {
+		return nil, err
+	}
+	byModel := make(map[int64]*fieldLayout)
+	var layouts []*fieldLayout
+	for _, m := range pkg.Models {
+		l := &fieldLayout{name: m.Name, fields: m.Fields}
+		byModel[m.ID] = l
+		layouts = append(layouts, l)
+	}
+	for _, n := range pkg.Notes {
+		l := byModel[n.ModelID]
+		if l == nil {
+			continue
+		}
+		values := make([]string, len(n.Fields))
+		for i, f := range n.Fields {
+			values[i] = anki.PlainText(f)
+		}
+		l.rows = append(l.rows, fieldRow{unit: strings.Join(n.Deck, "/"), values: values})
+	}
+	if len(pkg.Notes) == 0 {
+		return nil, errors.New("卡组中没有笔记")
+	}
+	return layouts, nil
+}
+
+// parseTable 解析 CSV/TSV。分隔符优先取 Anki 文本导出的 "#separator:" 声明，
+// 其次按扩展名，.csv/.txt 则按首行中制表符和逗号的多少判断
+func parseTable(data []byte, name, ext string) (*fieldLayout, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	sep := ','
+	if ext == ".tsv" {
+		sep = '\t'
+	}
+	declared := false
+	var body []string
+	for _, line := range strings.Split(string(data), "\n") {
+		// Anki 导出的文本开头有 "#html:true" 之类的声明
+		if len(body) == 0 && strings.HasPrefix(line, "#") && strings.Contains(line, ":") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(line), "#separator:"); ok {
+				if s, ok := map[string]rune{"tab": '\t', "comma": ',', "semicolon": ';', "pipe": '|'}[strings.ToLower(v)]; ok {
+					sep, declared = s, true
+				}
+			}
+			continue
+		}
+		body = append(body, line)
+	}
+	if !declared && ext != ".tsv" && len(body) > 0 && strings.Count(body[0], "\t") > strings.Count(body[0], ",") {
+		sep = '\t'
+	}
+
+	r := csv.NewReader(strings.NewReader(strings.Join(body, "\n")))
+	r.Comma = sep
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("表格解析失败: %w", err)
+	}
+
+	l := &fieldLayout{name: name, table: true}
+	for _, rec := range records {
+		values := make([]string, len(rec))
+		for i, v := range rec {
+			values[i] = strings.TrimSpace(v)
+		}
+		l.rows = append(l.rows, fieldRow{values: values})
+	}
+	if len(l.rows) == 0 {
+		return nil, errors.New("表格是空的")
+	}
+	// 第一行能认出字段名时当作表头
+	first := guessMapping(l.rows[0].values, true)
+	l.header = first.kana >= 0 || first.kanji >= 0 || first.chines >= 0
+	return l, nil
+}
+
+// 常见的字段名，用来猜测字段对应关系
+var (
+	kanaNames   = []string{"假名", "仮名", "かな", "kana", "reading", "読み", "读音", "furigana", "hiragana"}
+	kanjiNames  = []string{"汉字", "漢字", "kanji", "expression", "word", "vocab", "单词", "単語", "front"}
+	chinesNames = []string{"中文", "chinese", "释义", "意思", "meaning", "意味", "definition", "translation", "english", "back"}
+	unitNames   = []string{"单元", "unit", "lesson", "课", "deck", "牌组"}
+)
+
+// guessMapping 按字段名猜测对应关系；strict 为 false 且一个都认不出时按 假名、汉字、中文 的列顺序
+func guessMapping(fields []string, strict bool) fieldMapping {
+	m := fieldMapping{kana: -1, kanji: -1, chines: -1, unit: -1}
+	match := func(field string, names []string) bool {
+		f := strings.ToLower(field)
+		for _, n := range names {
+			if strings.Contains(f, n) {
+				return true
+			}
+		}
+		return false
+	}
+	for i, f := range fields {
+		switch {
+		case m.chines < 0 && match(f, chinesNames):
+			m.chines = i
+		case m.kana < 0 && match(f, kanaNames):
+			m.kana = i
+		case m.kanji < 0 && match(f, kanjiNames):
+			m.kanji = i
+		case m.unit < 0 && match(f, unitNames):
+			m.unit = i
+		}
+	}
+	if strict || m.kana >= 0 || m.kanji >= 0 || m.chines >= 0 {
+		return m
+	}
+	switch {
+	case len(fields) >= 3:
+		m.kana, m.kanji, m.chines = 0, 1, 2
+	case len(fields) == 2:
+		m.kana, m.chines = 0, 1
+	}
+	return m
+}
+
+// buildUnits 按对应关系生成各单元的单词，返回单元名到单词的映射和跳过的行数
+func buildUnits(packName string, layouts []*fieldLayout, mappings []fieldMapping) (map[string][]WordItem, int) {
+	units := make(map[string][]WordItem)
+	skipped := 0
+	for i, l := range layouts {
+		m := mappings[i]
+		for _, r := range l.data() {
+			value := func(col int) string {
+				if col < 0 || col >= len(r.values) {
+					return ""
+				}
+				return r.values[col]
+			}
+			w := WordItem{
+				Kana:   value(m.kana),
+				Kanji:  value(m.kanji),
+				Chines: splitMeanings(value(m.chines)),
+			}
+			if (w.Kana == "" && w.Kanji == "") || len(w.Chines) == 0 {
+				skipped++
+				continue
+			}
+			unit := r.unit
+			if u := value(m.unit); u != "" {
+				unit = u
+			}
+			if unit == "" {
+				unit = packName
+			}
+			units[unit] = append(units[unit], w)
+		}
+	}
+	return units, skipped
+}
+
+// splitMeanings 把一个字段里的多个释义拆开
+func splitMeanings(s string) []string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune("\n/／;；,，", r)
+	})
+	var res []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// showImportDeckDialog 选择 .apkg/.csv/.tsv 文件，设置字段对应后导入
+func showImportDeckDialog(win fyne.Window) {
+	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		ext := strings.ToLower(reader.URI().Extension())
+		name := strings.TrimSuffix(reader.URI().Name(), reader.URI().Extension())
+
+		var layouts []*fieldLayout
+		if ext == ".apkg" {
+			layouts, err = parseApkg(data)
+		} else {
+			var l *fieldLayout
+			l, err = parseTable(data, name, ext)
+			layouts = []*fieldLayout{l}
+		}
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		showFieldMappingDialog(win, name, layouts)
+	}, win)
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".apkg", ".csv", ".tsv", ".txt"}))
+	fd.Show()
+}
+
+// showFieldMappingDialog 每种字段布局一组下拉框，选择假名、汉字、中文释义（和单元）对应的字段
+func showFieldMappingDialog(win fyne.Window, packName string, layouts []*fieldLayout) {
+	const unused = "(不使用)"
+	mappings := make([]fieldMapping, len(layouts))
+	box := container.NewVBox()
+
+	for i, l := range layouts {
+		i, l := i, l
+		mappings[i] = guessMapping(l.columns(), false)
+
+		options := func() []string {
+			return append([]string{unused}, l.columns()...)
+		}
+		preview := widget.NewLabel("")
+		updatePreview := func() {
+			rows := l.data()
+			if len(rows) == 0 {
+				preview.SetText("")
+				return
+			}
+			units, _ := buildUnits(packName, []*fieldLayout{{rows: rows[:1], fields: l.fields}}, mappings[i:i+1])
+			for _, ws := range units {
+				w := ws[0]
+				preview.SetText(fmt.Sprintf("示例: %s / %s / %s", w.Kana, w.Kanji, strings.Join(w.Chines, "/")))
+				return
+			}
+			preview.SetText("示例: (第一条数据缺少假名/汉字或中文释义，将被跳过)")
+		}
+
+		newSelect := func(col *int) *widget.Select {
+			sel := widget.NewSelect(options(), nil)
+			sel.SetSelectedIndex(*col + 1)
+			sel.OnChanged = func(string) {
+				*col = sel.SelectedIndex() - 1
+				updatePreview()
+			}
+			return sel
+		}
+		m := &mappings[i]
+		kanaSel := newSelect(&m.kana)
+		kanjiSel := newSelect(&m.kanji)
+		chinesSel := newSelect(&m.chines)
+		selects := []*widget.Select{kanaSel, kanjiSel, chinesSel}
+
+		title := fmt.Sprintf("笔记类型: %s (%d 条)", l.name, len(l.rows))
+		form := widget.NewForm(
+			widget.NewFormItem("假名", kanaSel),
+			widget.NewFormItem("汉字", kanjiSel),
+			widget.NewFormItem("中文释义", chinesSel),
+		)
+		if l.table {
+			title = fmt.Sprintf("文件: %s (%d 行)", l.name, len(l.rows))
+			unitSel := newSelect(&m.unit)
+			selects = append(selects, unitSel)
+			form.Append("单元 (可选)", unitSel)
+
+			headerCheck := widget.NewCheck("第一行是表头", func(checked bool) {
+				l.header = checked
+				for _, sel := range selects {
+					idx := sel.SelectedIndex()
+					sel.Options = options()
+					sel.SetSelectedIndex(idx)
+				}
+				updatePreview()
+			})
+			headerCheck.SetChecked(l.header)
+			box.Add(widget.NewLabel(title))
+			box.Add(headerCheck)
+		} else {
+			box.Add(widget.NewLabel(title))
+		}
+		box.Add(form)
+		box.Add(preview)
+		updatePreview()
+	}
+
+	content := container.NewVScroll(box)
+	content.SetMinSize(fyne.NewSize(460, 360))
+	dialog.ShowCustomConfirm("导入 "+packName, "导入", "取消", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		units, skipped := buildUnits(packName, layouts, mappings)
+		if len(units) == 0 {
+			dialog.ShowError(errors.New("没有可以导入的单词，请检查字段对应关系"), win)
+			return
+		}
+		names := make([]string, 0, len(units))
+		for u := range units {
+			names = append(names, u)
+		}
+		sort.Strings(names)
+
+		n, err := importPack(packName, func(t *vocabTree, root *DirEntry) error {
+			for _, u := range names {
+				data, err := json.Marshal(units[u])
+				if err != nil {
+					return err
+				}
+				createDirEntry(t, root, u+".json", false, func() ([]byte, error) { return data, nil })
+			}
+			return nil
+		})
+		if err == nil && skipped > 0 {
+			dialog.ShowInformation("导入成功", fmt.Sprintf("已导入 %d 个单元，跳过 %d 条缺少假名/汉字或中文释义的数据", n, skipped), win)
+			return
+		}
+		showImportResult(n, err, win)
+	}, win)
+}
@@ -0,0 +1,228 @@
+package vocabulary
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ==================================================
+// 后台下载词库：可取消、显示真实进度、失败时指数退避重试
+// ==================================================
+
+const (
+	maxRetries     = 4
+	baseRetryDelay = 2 * time.Second // 第 n 次重试前等待 baseRetryDelay * 2^(n-1)
+)
+
+// progressFunc 报告已下载字节数和总字节数，total 未知时为 -1
+type progressFunc func(read, total int64)
+
+// progressReader 在读取时报告进度，最多每 progressInterval 报告一次，读完时一定报告
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	total    int64
+	progress progressFunc
+	last     time.Time
+}
+
+const progressInterval = 100 * time.Millisecond
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	p.read += int64(n)
+	if err != nil || time.Since(p.last) >= progressInterval {
+		p.last = time.Now()
+		p.progress(p.read, p.total)
+	}
+	return n, err
+}
+
+// downloadZip 下载 ZIP；有缓存时带上 If-None-Match/If-Modified-Since，
+// 服务器返回 304 时直接使用缓存，返回新内容时更新缓存
+func downloadZip(ctx context.Context, myApp fyne.App, url string, cache *cachedZip, progress progressFunc) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		if cache.meta.ETag != "" {
+			req.Header.Set("If-None-Match", cache.meta.ETag)
+		}
+		if cache.meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cache.meta.LastModified)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cache != nil {
+		return cache.data, nil
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP状态码=%d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(&progressReader{r: resp.Body, total: resp.ContentLength, progress: progress})
+	if err != nil {
+		return nil, err
+	}
+	// 先确认是有效的 ZIP 再更新缓存，避免错误页面等内容覆盖掉可用的缓存
+	if _, err := zip.NewReader(bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, fmt.Errorf("下载的内容不是有效的ZIP: %w", err)
+	}
+	if err := saveZipCache(myApp, url, data, resp.Header); err != nil {
+		fyne.LogError("保存词库缓存失败", err)
+	}
+	return data, nil
+}
+
+// loadResult 后台加载的结果
+type loadResult struct {
+	tree   *vocabTree
+	notice string
+	err    error
+}
+
+// fetchZip 下载来源的 ZIP，失败时按指数退避重试，全部失败后退回到本地缓存
+func fetchZip(ctx context.Context, myApp fyne.App, src Source, progress progressFunc, status func(string)) ([]byte, string, error) {
+	cache := loadZipCache(myApp, src.Location)
+
+	var data []byte
+	var err error
+	status("正在请求最新词库...")
+	for i := 0; i < maxRetries; i++ {
+		if i > 0 {
+			delay := baseRetryDelay << (i - 1)
+			status(fmt.Sprintf("下载失败，%d 秒后进行第 %d 次重试...", int(delay.Seconds()), i))
+			select {
+			case <-ctx.Done():
+				return nil, "", ctx.Err()
+			case <-time.After(delay):
+			}
+			status("正在请求最新词库...")
+		}
+
+		data, err = downloadZip(ctx, myApp, src.Location, cache, progress)
+		if err == nil {
+			fmt.Println("Operation succeeded")
+			return data, "", nil
+		}
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+	}
+
+	// 下载失败时退回到本地缓存
+	if cache != nil {
+		fyne.LogError("下载词库失败，使用本地缓存", err)
+		return cache.data, cache.offlineNotice(src.Name), nil
+	}
+	return nil, "", err
+}
+
+// fetchVocabulary 在后台线程中依次加载所有词库来源。某个来源失败时跳过它，
+// 全部失败时返回错误；只要不是被取消，返回的 tree 都不为 nil，以便仍可进入主页面修改设置
+func fetchVocabulary(ctx context.Context, myApp fyne.App, sources []Source, progress progressFunc, status func(string)) loadResult {
+	t := newVocabTree()
+	if len(sources) == 0 {
+		return loadResult{tree: t, notice: "还没有配置词库来源，请在\"词库来源设置\"中添加"}
+	}
+
+	var notices, failed []string
+	for i, src := range sources {
+		prefix := fmt.Sprintf("[%d/%d] %s: ", i+1, len(sources), src.Name)
+		notice, err := loadSource(ctx, myApp, t, src, progress, func(s string) { status(prefix + s) })
+		if ctx.Err() != nil {
+			return loadResult{err: ctx.Err()}
+		}
+		if err != nil {
+			fyne.LogError("加载词库失败: "+src.Name, err)
+			failed = append(failed, fmt.Sprintf("%s: %v", src.Name, err))
+			continue
+		}
+		if notice != "" {
+			notices = append(notices, notice)
+		}
+	}
+
+	if len(failed) == len(sources) {
+		return loadResult{tree: t, err: fmt.Errorf("词库加载失败\n%s", strings.Join(failed, "\n"))}
+	}
+	if len(failed) > 0 {
+		notices = append(notices, "以下词库加载失败: "+strings.Join(failed, "; "))
+	}
+	return loadResult{tree: t, notice: strings.Join(notices, "\n")}
+}
+
+// loadVocabularyAsync 显示带进度条和取消按钮的对话框，在后台加载所有词库来源，
+// 完成后把词库交给界面并调用 onLoaded
+func loadVocabularyAsync(myApp fyne.App, parent fyne.Window, onLoaded func(notice string)) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	statusLabel := widget.NewLabel("正在加载词库...")
+	bar := widget.NewProgressBar()
+	bar.Hide()
+	infinite := widget.NewProgressBarInfinite()
+	sizeLabel := widget.NewLabel("")
+
+	cancelBtn := widget.NewButton("取消", cancel)
+	dlg := dialog.NewCustomWithoutButtons("提示",
+		container.NewVBox(statusLabel, bar, infinite, sizeLabel, cancelBtn), parent)
+	dlg.Resize(fyne.NewSize(360, 200))
+	dlg.Show()
+
+	// 进度和状态由后台线程报告，通过 fyne.Do 交回界面线程更新控件
+	progress := func(read, total int64) {
+		fyne.Do(func() {
+			if total > 0 {
+				infinite.Stop()
+				infinite.Hide()
+				bar.Show()
+				bar.SetValue(float64(read) / float64(total))
+				sizeLabel.SetText(fmt.Sprintf("%.1f / %.1f MB", float64(read)/1e6, float64(total)/1e6))
+			} else {
+				sizeLabel.SetText(fmt.Sprintf("已下载 %.1f MB", float64(read)/1e6))
+			}
+		})
+	}
+	status := func(s string) {
+		fyne.Do(func() { statusLabel.SetText(s) })
+	}
+
+	// 后台线程只负责下载和解析，解析好的词库最后回到界面线程通过 setVocabTree 一次性交给界面
+	go func() {
+		res := fetchVocabulary(ctx, myApp, loadSources(myApp), progress, status)
+		cancel()
+
+		fyne.Do(func() {
+			dlg.Hide()
+			if errors.Is(res.err, context.Canceled) {
+				return
+			}
+			if res.err != nil {
+				dialog.ShowError(res.err, parent)
+			}
+			if res.tree == nil {
+				return
+			}
+			setVocabTree(res.tree)
+			onLoaded(res.notice)
+		})
+	}()
+}
@@ -0,0 +1,183 @@
+package vocabulary
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/anki"
+	"FiftySound/modules/appdata"
+	"FiftySound/modules/srs"
+)
+
+// ==================================================
+// 导出选中的单词：CSV、JSON（与单元文件格式相同，可以再导入）或 Anki 卡组包，
+// 可以附带每个单词的复习进度
+// ==================================================
+
+// 导出格式
+const (
+	formatCSV  = "CSV"
+	formatJSON = "JSON"
+	formatApkg = "Anki 卡组 (.apkg)"
+)
+
+var formatExt = map[string]string{
+	formatCSV:  ".csv",
+	formatJSON: ".json",
+	formatApkg: ".apkg",
+}
+
+// exportedWord JSON 导出的一条单词
+type exportedWord struct {
+	WordItem
+	UnitName string    `json:"单元,omitempty"`
+	Progress *srs.Card `json:"复习进度,omitempty"`
+}
+
+// showExportDialog 选择格式和是否包含复习进度，然后选择保存位置
+func showExportDialog(myApp fyne.App, win fyne.Window) {
+	words := uniqueWords(selectedWords)
+	if len(words) == 0 {
+		dialog.ShowInformation("提示", "请先选择单词", win)
+		return
+	}
+
+	formatSelect := widget.NewSelect([]string{formatCSV, formatJSON, formatApkg}, nil)
+	formatSelect.SetSelected(formatCSV)
+	progressCheck := widget.NewCheck("包含复习进度", nil)
+
+	content := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("导出当前选中的 %d 个单词", len(words))),
+		formatSelect,
+		progressCheck,
+	)
+	dialog.ShowCustomConfirm("导出单词", "导出", "取消", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		var sched *srs.Scheduler
+		if progressCheck.Checked {
+			sched = wordScheduler(myApp)
+		}
+		format := formatSelect.Selected
+		data, err := exportWords(format, words, sched)
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		appdata.SaveAs(win, "单词"+formatExt[format], data)
+	}, win)
+}
+
+// uniqueWords 去掉重复的单词（同一个单词可能出现在多个单元中）
+func uniqueWords(words []WordItem) []WordItem {
+	seen := make(map[string]bool)
+	var res []WordItem
+	for _, w := range words {
+		if k := wordKey(w); !seen[k] {
+			seen[k] = true
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+// exportWords 按格式生成文件内容，sched 为 nil 时不包含复习进度
+func exportWords(format string, words []WordItem, sched *srs.Scheduler) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		return wordsJSON(words, sched)
+	case formatApkg:
+		return wordsApkg(words, sched)
+	}
+	return wordsCSV(words, sched)
+}
+
+func wordsCSV(words []WordItem, sched *srs.Scheduler) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\xef\xbb\xbf") // BOM，让 Excel 按 UTF-8 打开
+	w := csv.NewWriter(&buf)
+
+	header := []string{"假名", "日本汉字", "中文释义", "单元"}
+	if sched != nil {
+		header = append(header, progressHeader...)
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, word := range words {
+		rec := []string{word.Kana, word.Kanji, strings.Join(word.Chines, "/"), word.Unit}
+		if sched != nil {
+			rec = append(rec, progressColumns(sched, wordKey(word))...)
+		}
+		if err := w.Write(rec); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// CSV 中复习进度的各列
+var progressHeader = []string{"到期时间", "间隔(天)", "难度系数", "连续答对", "遗忘次数"}
+
+func progressColumns(sched *srs.Scheduler, key string) []string {
+	c, ok := sched.Card(key)
+	if !ok {
+		return make([]string, len(progressHeader))
+	}
+	return []string{
+		c.Due.Local().Format("2006-01-02 15:04"),
+		fmt.Sprintf("%.1f", c.Interval),
+		fmt.Sprintf("%.2f", c.Ease),
+		fmt.Sprint(c.Repetitions),
+		fmt.Sprint(c.Lapses),
+	}
+}
+
+func wordsJSON(words []WordItem, sched *srs.Scheduler) ([]byte, error) {
+	res := make([]exportedWord, len(words))
+	for i, w := range words {
+		res[i] = exportedWord{WordItem: w, UnitName: w.Unit}
+		if sched != nil {
+			if c, ok := sched.Card(wordKey(w)); ok {
+				res[i].Progress = &c
+			}
+		}
+	}
+	return json.MarshalIndent(res, "", "  ")
+}
+
+func wordsApkg(words []WordItem, sched *srs.Scheduler) ([]byte, error) {
+	deck := anki.Deck{
+		Name:   "FiftySound单词",
+		Model:  "FiftySound单词",
+		Fields: []string{"假名", "汉字", "中文释义"},
+		Front:  "<div style='font-size:36px'>{{假名}}</div>{{#汉字}}<div>{{汉字}}</div>{{/汉字}}",
+		Back:   "{{FrontSide}}<hr id=answer>{{中文释义}}",
+	}
+	for _, w := range words {
+		n := anki.ExportNote{
+			Key:    wordKey(w),
+			Fields: []string{w.Kana, w.Kanji, strings.Join(w.Chines, " / ")},
+		}
+		// 单元 "标准日本语第二版/初级上/第1课" 对应子牌组
+		if w.Unit != "" {
+			n.Deck = strings.Split(w.Unit, "/")
+		}
+		if sched != nil {
+			c, ok := sched.Card(wordKey(w))
+			n.State = anki.StateFromSRS(c, ok)
+		}
+		deck.Notes = append(deck.Notes, n)
+	}
+	return anki.WritePackage(deck)
+}
@@ -0,0 +1,204 @@
+package vocabulary
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/history"
+	"FiftySound/modules/session"
+	"FiftySound/modules/srs"
+)
+
+// ==================================================
+// 模式3: 背单词（闪卡）
+// 正面显示中文、假名或汉字中的一项，翻面后显示全部内容，
+// 再按"重来/困难/良好/简单"自评；自评结果直接用于间隔重复调度，
+// 选"重来"的单词几分钟后会在本次练习中再次出现（题量为"每个各一次"时除外，
+// 那时每个单词只出现一次，"重来"的单词记为错题，可以在总结中"只练错题"）
+// ==================================================
+
+const flashcardFrontPrefKey = "vocabulary.flashcard_front"
+
+// 闪卡正面显示的内容
+const (
+	frontChinese = "中文"
+	frontKana    = "假名"
+	frontKanji   = "汉字"
+)
+
+var flashcardFronts = []string{frontChinese, frontKana, frontKanji}
+
+// gradeNames 自评按钮的文字，顺序与 srs.Grade 一致
+var gradeNames = []string{
+	srs.Again: "重来",
+	srs.Hard:  "困难",
+	srs.Good:  "良好",
+	srs.Easy:  "简单",
+}
+
+// grade 记录背单词的自评：写入答题记录（"重来"算答错），并按自评更新复习进度
+func (p *WordPool) grade(w WordItem, g srs.Grade) {
+	p.log.Add(history.Record{
+		Module:  history.ModuleWord,
+		Mode:    p.mode,
+		Item:    wordKey(w),
+		Group:   w.Unit,
+		Answer:  gradeNames[g],
+		Correct: g != srs.Again,
+		Latency: time.Since(p.shownAt),
+	})
+	if err := p.sched.Review(wordKey(w), g); err != nil {
+		fyne.LogError("保存复习进度失败", err)
+	}
+}
+
+// cardFront 闪卡正面；没有汉字的单词以假名作为正面
+func cardFront(myApp fyne.App, w WordItem, front string) string {
+	switch {
+	case front == frontChinese:
+		return strings.Join(w.Chines, "/")
+	case front == frontKanji && w.Kanji != "":
+		return w.Kanji
+	}
+	return withRomaji(myApp, w.Kana)
+}
+
+// cardBack 闪卡翻面后显示的全部内容
+func cardBack(myApp fyne.App, w WordItem) string {
+	return fmt.Sprintf("[中文] %s\n[假名] %s\n[汉字] %s",
+		strings.Join(w.Chines, "/"), withRomaji(myApp, w.Kana), w.Kanji)
+}
+
+func showModeThreeWords(myApp fyne.App, parent fyne.Window, words []WordItem, length session.Length) {
+	win := myApp.NewWindow(modeThreeWordsName)
+
+	pool := newWordPool(myApp, modeThreeWordsName, words, length)
+	progress := widget.NewLabel("")
+	frontLabel := widget.NewLabel("")
+	frontLabel.Alignment = fyne.TextAlignCenter
+	frontLabel.TextStyle = fyne.TextStyle{Bold: true}
+	backLabel := widget.NewLabel("")
+
+	front := myApp.Preferences().StringWithFallback(flashcardFrontPrefKey, frontChinese)
+	frontRadio := widget.NewRadioGroup(flashcardFronts, nil)
+	frontRadio.Horizontal = true
+	frontRadio.SetSelected(front)
+
+	var current WordItem
+	flipped := false
+
+	var flipBtn *widget.Button
+	gradeBtns := make([]*widget.Button, len(gradeNames))
+	setGrading := func(on bool) {
+		for _, b := range gradeBtns {
+			if on {
+				b.Enable()
+			} else {
+				b.Disable()
+			}
+		}
+		if on {
+			flipBtn.Disable()
+		} else {
+			flipBtn.Enable()
+		}
+	}
+
+	showOne := func() {
+		if pool.sess.Finished() {
+			session.ShowSummary(win, pool.sess, func(missed []string) {
+				win.Close()
+				showModeThreeWords(myApp, parent, pool.missedWords(missed), session.EachOnce)
+			})
+			return
+		}
+		progress.SetText(pool.sess.Progress())
+		current = pool.nextWord()
+		flipped = false
+		frontLabel.SetText(cardFront(myApp, current, front))
+		backLabel.SetText("")
+		setGrading(false)
+	}
+
+	flip := func() {
+		if flipped {
+			return
+		}
+		flipped = true
+		backLabel.SetText(cardBack(myApp, current))
+		setGrading(true)
+	}
+
+	rate := func(g srs.Grade) {
+		if !flipped || pool.sess.Finished() {
+			return
+		}
+		pool.grade(current, g)
+		pool.sess.Add(wordKey(current), g != srs.Again, session.Mistake{
+			Question: frontLabel.Text,
+			Answer:   gradeNames[g],
+			Expected: strings.ReplaceAll(cardBack(myApp, current), "\n", "  "),
+		})
+		showOne()
+	}
+
+	flipBtn = widget.NewButton("翻面", flip)
+	for i, name := range gradeNames {
+		g := srs.Grade(i)
+		gradeBtns[i] = widget.NewButton(fmt.Sprintf("%d. %s", i+1, name), func() { rate(g) })
+	}
+
+	// 切换正面时当前卡片重新显示正面
+	frontRadio.OnChanged = func(s string) {
+		if s == "" {
+			return
+		}
+		front = s
+		myApp.Preferences().SetString(flashcardFrontPrefKey, s)
+		flipped = false
+		frontLabel.SetText(cardFront(myApp, current, front))
+		backLabel.SetText("")
+		setGrading(false)
+	}
+
+	// 空格或回车翻面，数字键 1-4 自评
+	win.Canvas().SetOnTypedKey(func(e *fyne.KeyEvent) {
+		switch e.Name {
+		case fyne.KeySpace, fyne.KeyReturn, fyne.KeyEnter:
+			flip()
+		}
+	})
+	win.Canvas().SetOnTypedRune(func(r rune) {
+		if r >= '1' && int(r-'1') < len(gradeNames) {
+			rate(srs.Grade(r - '1'))
+		}
+	})
+
+	closeBtn := widget.NewButton("关闭", func() {
+		win.Close()
+	})
+
+	grades := container.NewGridWithColumns(len(gradeBtns))
+	for _, b := range gradeBtns {
+		grades.Add(b)
+	}
+
+	win.SetContent(container.NewVBox(
+		container.NewHBox(widget.NewLabel("正面："), frontRadio),
+		progress,
+		frontLabel,
+		flipBtn,
+		backLabel,
+		grades,
+		widget.NewLabel("空格或回车翻面，数字键 1-4 自评"),
+		closeBtn,
+	))
+	win.Resize(fyne.NewSize(420, 360))
+	showOne()
+	win.Show()
+}
@@ -0,0 +1,165 @@
+package vocabulary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// ==================================================
+// 导入本地单元包：文件夹或 ZIP，文件格式与词库相同（WordItem 数组的 JSON）
+// 导入的单元包作为新的顶层节点出现在单元选择树中
+// ==================================================
+
+// importedRoot 导入的单元包在目录树中的路径前缀
+const importedRoot = "IMPORT"
+
+// showImportFolderDialog 选择本地文件夹并导入
+func showImportFolderDialog(win fyne.Window) {
+	dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		if dir == nil {
+			return
+		}
+		n, err := importPack(dir.Name(), func(t *vocabTree, root *DirEntry) error {
+			return addFolderEntries(t, root, dir, "")
+		})
+		showImportResult(n, err, win)
+	}, win)
+}
+
+// showImportZipDialog 选择本地 ZIP 文件并导入
+func showImportZipDialog(win fyne.Window) {
+	fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		name := strings.TrimSuffix(reader.URI().Name(), reader.URI().Extension())
+		n, err := importPack(name, func(t *vocabTree, root *DirEntry) error {
+			return addZipEntries(t, root, data, true)
+		})
+		showImportResult(n, err, win)
+	}, win)
+	fd.SetFilter(storage.NewExtensionFileFilter([]string{".zip"}))
+	fd.Show()
+}
+
+func showImportResult(n int, err error, win fyne.Window) {
+	if err != nil {
+		dialog.ShowError(err, win)
+		return
+	}
+	dialog.ShowInformation("导入成功", fmt.Sprintf("已导入 %d 个单元，请在\"选择需要练习的单元\"中查看", n), win)
+}
+
+// importPack 在当前词库中新建名为 name 的顶层节点，由 fill 填充内容，
+// 同名的单元包会被替换。返回可用的单元（JSON 文件）数
+func importPack(name string, fill func(t *vocabTree, root *DirEntry) error) (int, error) {
+	t := currentTree()
+	if t == nil {
+		return 0, errors.New("词库尚未加载")
+	}
+
+	treeMu.Lock()
+	defer treeMu.Unlock()
+
+	fullPath := importedRoot + "/" + name
+	tmp := newPackTree(fullPath, name)
+	root := tmp.root
+	if err := fill(tmp, root); err != nil {
+		return 0, err
+	}
+
+	var files []string
+	collectJSON(root, &files)
+	valid := 0
+	var invalid []string
+	for _, f := range files {
+		var arr []WordItem
+		if err := json.Unmarshal(tmp.contents[f], &arr); err != nil {
+			invalid = append(invalid, strings.TrimPrefix(f, fullPath+"/"))
+			continue
+		}
+		valid++
+	}
+	if valid == 0 {
+		return 0, fmt.Errorf("%s 中没有找到可用的单元文件 (JSON)", name)
+	}
+	if len(invalid) > 0 {
+		return 0, fmt.Errorf("以下文件不是有效的单元文件: %s", strings.Join(invalid, ", "))
+	}
+
+	removePack(t, fullPath)
+	t.merge(tmp)
+	t.roots = append(t.roots, root)
+	return valid, nil
+}
+
+// removePack 删除已导入的同名单元包（调用方需持有 treeMu）
+func removePack(t *vocabTree, fullPath string) {
+	for i, r := range t.roots {
+		if r.FullPath == fullPath {
+			t.roots = append(t.roots[:i], t.roots[i+1:]...)
+			break
+		}
+	}
+	for k := range t.index {
+		if k == fullPath || strings.HasPrefix(k, fullPath+"/") {
+			delete(t.index, k)
+			delete(t.contents, k)
+		}
+	}
+}
+
+// addFolderEntries 递归把 dir 下的 JSON 文件挂到 root 下，prefix 为相对路径
+func addFolderEntries(t *vocabTree, root *DirEntry, dir fyne.ListableURI, prefix string) error {
+	items, err := dir.List()
+	if err != nil {
+		return err
+	}
+	for _, u := range items {
+		if ok, _ := storage.CanList(u); ok {
+			sub, err := storage.ListerForURI(u)
+			if err != nil {
+				return err
+			}
+			if err := addFolderEntries(t, root, sub, prefix+u.Name()+"/"); err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasSuffix(strings.ToLower(u.Name()), ".json") {
+			continue
+		}
+		fileURI := u
+		createDirEntry(t, root, prefix+u.Name(), false, func() ([]byte, error) {
+			rc, err := storage.Reader(fileURI)
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		})
+	}
+	return nil
+}
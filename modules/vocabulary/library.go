@@ -0,0 +1,46 @@
+package vocabulary
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// ==================================================
+// 供其它模块使用的词库接口
+// ==================================================
+
+// LibraryWords 取词库（所有词库来源和导入的单元包）中的全部单词，同一个单词只出现一次。
+// 词库还没有加载时先在后台加载（显示进度对话框），加载完成后调用 onLoaded
+func LibraryWords(myApp fyne.App, parent fyne.Window, onLoaded func(words []WordItem)) {
+	if currentTree() != nil {
+		onLoaded(allWords())
+		return
+	}
+	loadVocabularyAsync(myApp, parent, func(string) {
+		onLoaded(allWords())
+	})
+}
+
+// allWords 当前词库中的全部单词
+func allWords() []WordItem {
+	t := currentTree()
+	if t == nil {
+		return nil
+	}
+	var files []string
+	treeMu.RLock()
+	for _, r := range t.roots {
+		collectJSON(r, &files)
+	}
+	treeMu.RUnlock()
+
+	var words []WordItem
+	for _, f := range files {
+		arr, err := loadJSON(f)
+		if err != nil {
+			fyne.LogError("读取单元失败: "+f, err)
+			continue
+		}
+		words = append(words, arr...)
+	}
+	return uniqueWords(words)
+}
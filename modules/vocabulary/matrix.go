@@ -0,0 +1,381 @@
+package vocabulary
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/romaji"
+	"FiftySound/modules/session"
+)
+
+// ==================================================
+// 模式4: 自定义练习
+// 自由组合题目显示的内容和需要作答的内容（中文、假名、汉字、罗马音），
+// 如 "汉字 => 假名" 练读音、"假名 => 汉字" 练写法；每一项分别判题，
+// 全部答对才算这道题答对。组合写在模式名里，如 "模式4: 汉字 => 假名&罗马音"，
+// 答题记录和错题本按组合分别统计
+// ==================================================
+
+const (
+	matrixModePrefix = "模式4: "
+	matrixPrefKey    = "vocabulary.matrix"
+)
+
+// wordField 单词的一项内容
+type wordField int
+
+const (
+	fieldChinese wordField = iota
+	fieldKana
+	fieldKanji
+	fieldRomaji
+)
+
+var fieldNames = []string{
+	fieldChinese: "中文",
+	fieldKana:    "假名",
+	fieldKanji:   "汉字",
+	fieldRomaji:  "罗马音",
+}
+
+func (f wordField) String() string {
+	return fieldNames[f]
+}
+
+// fieldMatrix 一种题目组合：显示 prompt 中的各项，作答 answer 中的各项
+type fieldMatrix struct {
+	prompt []wordField
+	answer []wordField
+	sys    romaji.System // 显示罗马音时使用的拼写方式
+}
+
+// 常用组合，可以在设置窗口中直接选择
+var matrixPresets = []string{
+	"汉字 => 假名",
+	"假名 => 汉字",
+	"中文 => 假名&汉字",
+	"假名&汉字 => 中文",
+	"罗马音 => 假名",
+	"假名 => 罗马音",
+	"汉字 => 假名&中文",
+}
+
+// fieldList 各项的名字
+func fieldList(fields []wordField) []string {
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.String())
+	}
+	return names
+}
+
+// String 如 "汉字 => 假名&罗马音"
+func (m fieldMatrix) String() string {
+	return strings.Join(fieldList(m.prompt), "&") + " => " + strings.Join(fieldList(m.answer), "&")
+}
+
+// mode 答题记录和错题本中使用的模式名
+func (m fieldMatrix) mode() string {
+	return matrixModePrefix + m.String()
+}
+
+// parseMatrix 解析 String 或 mode 的结果
+func parseMatrix(s string) (fieldMatrix, error) {
+	s = strings.TrimPrefix(s, matrixModePrefix)
+	prompt, answer, ok := strings.Cut(s, " => ")
+	if !ok {
+		return fieldMatrix{}, fmt.Errorf("无法识别的题目组合: %q", s)
+	}
+	parse := func(part string) ([]wordField, error) {
+		var res []wordField
+		for _, name := range strings.Split(part, "&") {
+			i := indexOf(fieldNames, strings.TrimSpace(name))
+			if i < 0 {
+				return nil, fmt.Errorf("无法识别的单词内容: %q", name)
+			}
+			res = append(res, wordField(i))
+		}
+		return res, nil
+	}
+	var m fieldMatrix
+	var err error
+	if m.prompt, err = parse(prompt); err != nil {
+		return fieldMatrix{}, err
+	}
+	if m.answer, err = parse(answer); err != nil {
+		return fieldMatrix{}, err
+	}
+	return m, m.validate()
+}
+
+// validate 显示和作答都至少要有一项，同一项不能既显示又作答
+func (m fieldMatrix) validate() error {
+	if len(m.prompt) == 0 {
+		return errors.New("请至少选择一项题目显示的内容")
+	}
+	if len(m.answer) == 0 {
+		return errors.New("请至少选择一项需要作答的内容")
+	}
+	for _, f := range m.answer {
+		for _, p := range m.prompt {
+			if f == p {
+				return fmt.Errorf("\"%s\" 不能既显示又作答", f)
+			}
+		}
+	}
+	return nil
+}
+
+// value 单词中 f 这一项的内容
+func (m fieldMatrix) value(w WordItem, f wordField) string {
+	switch f {
+	case fieldChinese:
+		return strings.Join(w.Chines, "/")
+	case fieldKana:
+		return w.Kana
+	case fieldKanji:
+		return w.Kanji
+	}
+	return romaji.ToRomaji(w.Kana, m.sys)
+}
+
+// usable 显示的内容至少有一项不为空，如只显示汉字时跳过没有汉字的单词
+func (m fieldMatrix) usable(w WordItem) bool {
+	for _, f := range m.prompt {
+		if m.value(w, f) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// question 题目，如 "汉字: 学校"，空的内容不显示
+func (m fieldMatrix) question(w WordItem) string {
+	var parts []string
+	for _, f := range m.prompt {
+		if v := m.value(w, f); v != "" {
+			parts = append(parts, f.String()+": "+v)
+		}
+	}
+	return strings.Join(parts, "    ")
+}
+
+// expected 所有作答项的正确答案，如 "がっこう / gakkou"
+func (m fieldMatrix) expected(w WordItem) string {
+	var parts []string
+	for _, f := range m.answer {
+		parts = append(parts, m.value(w, f))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// correct 判断 f 这一项的作答：中文答对任意一个释义即可，
+// 假名不区分平假名和片假名，也可以输入罗马音（见 kanaAnswerCorrect），
+// 罗马音平文式、训令式、日本式都算对
+func (m fieldMatrix) correct(w WordItem, f wordField, ans string) bool {
+	switch f {
+	case fieldChinese:
+		return indexOf(w.Chines, ans) >= 0
+	case fieldKana:
+		return kanaAnswerCorrect(ans, w.Kana)
+	case fieldKanji:
+		return ans == w.Kanji
+	}
+	return romaji.IsRomaji(ans) && romaji.Match(ans, w.Kana)
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// loadMatrix 上次使用的组合，默认 "汉字 => 假名"
+func loadMatrix(myApp fyne.App) fieldMatrix {
+	m, err := parseMatrix(myApp.Preferences().StringWithFallback(matrixPrefKey, matrixPresets[0]))
+	if err != nil {
+		m, _ = parseMatrix(matrixPresets[0])
+	}
+	return m
+}
+
+// showMatrixSettings 选择题目组合，确定后开始练习
+func showMatrixSettings(myApp fyne.App, parent fyne.Window, words []WordItem, length session.Length) {
+	m := loadMatrix(myApp)
+	promptCheck := widget.NewCheckGroup(fieldNames, nil)
+	promptCheck.Horizontal = true
+	promptCheck.SetSelected(fieldList(m.prompt))
+	answerCheck := widget.NewCheckGroup(fieldNames, nil)
+	answerCheck.Horizontal = true
+	answerCheck.SetSelected(fieldList(m.answer))
+
+	presetSelect := widget.NewSelect(matrixPresets, func(s string) {
+		p, err := parseMatrix(s)
+		if err != nil {
+			return
+		}
+		promptCheck.SetSelected(fieldList(p.prompt))
+		answerCheck.SetSelected(fieldList(p.answer))
+	})
+	presetSelect.PlaceHolder = "常用组合"
+
+	// CheckGroup 的选中顺序与点击顺序有关，按 fieldNames 的顺序整理
+	selected := func(c *widget.CheckGroup) []wordField {
+		var res []wordField
+		for i, name := range fieldNames {
+			if indexOf(c.Selected, name) >= 0 {
+				res = append(res, wordField(i))
+			}
+		}
+		return res
+	}
+
+	content := container.NewVBox(
+		presetSelect,
+		widget.NewLabel("题目显示："), promptCheck,
+		widget.NewLabel("需要作答："), answerCheck,
+	)
+	dialog.ShowCustomConfirm("自定义练习", "开始", "取消", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		m := fieldMatrix{prompt: selected(promptCheck), answer: selected(answerCheck)}
+		if err := m.validate(); err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		myApp.Preferences().SetString(matrixPrefKey, m.String())
+		showMatrixWords(myApp, parent, words, m, length, false)
+	}, parent)
+}
+
+// showMatrixWords 按组合 m 练习；practice 为 true 时是错题本中的"只练错题"
+func showMatrixWords(myApp fyne.App, parent fyne.Window, words []WordItem, m fieldMatrix, length session.Length, practice bool) {
+	if sys, ok := romajiSystem(myApp); ok {
+		m.sys = sys
+	}
+	var usable []WordItem
+	for _, w := range words {
+		if m.usable(w) {
+			usable = append(usable, w)
+		}
+	}
+	if len(usable) == 0 {
+		dialog.ShowInformation("提示", "所选单词都没有可以显示的"+strings.Join(fieldList(m.prompt), "或"), parent)
+		return
+	}
+
+	win := myApp.NewWindow(practiceTitle(m.mode(), practice))
+
+	pool := newWordPool(myApp, m.mode(), usable, length)
+	pool.practice = practice
+	pool.matrix = &m
+	progress := widget.NewLabel("")
+	question := widget.NewLabel("")
+	feedback := widget.NewLabel("")
+	openedAt := time.Now()
+	statsLabel := widget.NewLabel(pool.accuracyText(openedAt))
+
+	// 每个作答项一个输入框和一行判题结果
+	entries := make([]*widget.Entry, len(m.answer))
+	given := make([]func() string, len(m.answer))
+	results := make([]*widget.Label, len(m.answer))
+	form := container.NewVBox()
+	for i, f := range m.answer {
+		var input fyne.CanvasObject
+		switch f {
+		case fieldKana:
+			e := romaji.NewKanaEntry(romaji.Hiragana)
+			e.SetPlaceHolder("可以直接输入罗马音")
+			entries[i], given[i], input = &e.Entry, e.Kana, e
+		default:
+			e := widget.NewEntry()
+			if f == fieldRomaji {
+				e.SetPlaceHolder("平文式、训令式、日本式都可以")
+			}
+			entries[i], given[i], input = e, func() string { return strings.TrimSpace(e.Text) }, e
+		}
+		results[i] = widget.NewLabel("")
+		form.Add(widget.NewLabel(f.String() + "："))
+		form.Add(input)
+		form.Add(results[i])
+	}
+
+	var current WordItem
+
+	refresh := func() {
+		if pool.sess.Finished() {
+			session.ShowSummary(win, pool.sess, func(missed []string) {
+				win.Close()
+				showMatrixWords(myApp, parent, pool.missedWords(missed), m, session.EachOnce, practice)
+			})
+			return
+		}
+		progress.SetText(pool.sess.Progress())
+		for i := range entries {
+			entries[i].SetText("")
+			results[i].SetText("")
+		}
+		feedback.SetText("")
+		current = pool.nextWord()
+		question.SetText(m.question(current))
+	}
+
+	judgeBtn := widget.NewButton("判题", func() {
+		var answers []string
+		correct := true
+		for i, f := range m.answer {
+			ans := given[i]()
+			answers = append(answers, ans)
+			if m.correct(current, f, ans) {
+				results[i].SetText("✓ 正确")
+			} else {
+				correct = false
+				results[i].SetText("✗ 正确答案: " + m.value(current, f))
+			}
+		}
+		answer := strings.Join(answers, " / ")
+		cleared := pool.answer(current, answer, correct, session.Mistake{
+			Question: question.Text,
+			Answer:   answer,
+			Expected: m.expected(current),
+		})
+		if correct {
+			feedback.SetText("全部正确！" + clearedText(myApp, cleared))
+		} else {
+			feedback.SetText("错误，正确答案: " + m.expected(current))
+		}
+		statsLabel.SetText(pool.accuracyText(openedAt))
+	})
+
+	nextBtn := widget.NewButton("下一题", func() {
+		refresh()
+	})
+
+	closeBtn := widget.NewButton("关闭", func() {
+		win.Close()
+	})
+
+	win.SetContent(container.NewVBox(
+		progress,
+		question,
+		form,
+		container.NewHBox(judgeBtn, nextBtn),
+		feedback,
+		statsLabel,
+		closeBtn,
+	))
+	win.Resize(fyne.NewSize(400, 300))
+	refresh()
+	win.Show()
+}
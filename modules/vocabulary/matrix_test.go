@@ -0,0 +1,102 @@
+package vocabulary
+
+import (
+	"reflect"
+	"testing"
+
+	"FiftySound/modules/romaji"
+)
+
+func TestParseMatrixRoundTrip(t *testing.T) {
+	for _, s := range append(matrixPresets, "中文&罗马音 => 假名&汉字") {
+		m, err := parseMatrix(s)
+		if err != nil {
+			t.Errorf("parseMatrix(%q): %v", s, err)
+			continue
+		}
+		if got := m.String(); got != s {
+			t.Errorf("parseMatrix(%q).String() = %q", s, got)
+		}
+		// 答题记录中保存的模式名也能解析回同一个组合
+		back, err := parseMatrix(m.mode())
+		if err != nil || !reflect.DeepEqual(back, m) {
+			t.Errorf("parseMatrix(%q) = %+v, %v, want %+v", m.mode(), back, err, m)
+		}
+	}
+
+	m, err := parseMatrix("汉字 & 中文 => 假名")
+	if err != nil || !reflect.DeepEqual(m.prompt, []wordField{fieldKanji, fieldChinese}) {
+		t.Errorf("spaces around &: %+v, %v", m, err)
+	}
+}
+
+func TestParseMatrixInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"汉字",
+		"汉字 => ",
+		" => 假名",
+		"汉字 => 拼音",
+		"汉字 => 汉字",
+		"汉字&假名 => 中文&假名",
+		"模式4: 假名 => 假名",
+	} {
+		if m, err := parseMatrix(s); err == nil {
+			t.Errorf("parseMatrix(%q) = %+v, want error", s, m)
+		}
+	}
+}
+
+func TestMatrixCorrect(t *testing.T) {
+	school := WordItem{Kana: "がっこう", Kanji: "学校", Chines: []string{"学校", "校园"}}
+	coffee := WordItem{Kana: "コーヒー", Chines: []string{"咖啡"}}
+	m := fieldMatrix{sys: romaji.Hepburn}
+	tests := []struct {
+		w    WordItem
+		f    wordField
+		ans  string
+		want bool
+	}{
+		{school, fieldChinese, "校园", true},
+		{school, fieldChinese, "学校/校园", false},
+		{school, fieldKana, "がっこう", true},
+		{school, fieldKana, "gakkou", true},
+		{school, fieldKana, "がこう", false},
+		{school, fieldKanji, "学校", true},
+		{school, fieldKanji, "がっこう", false},
+		{school, fieldRomaji, "gakkou", true},
+		{school, fieldRomaji, "gakkō", true},
+		{school, fieldRomaji, "がっこう", false},
+		// 假名输入框固定转换成平假名，片假名的单词输入罗马音也要算对
+		{coffee, fieldKana, "こーひー", true},
+		{coffee, fieldKana, "ko-hi-", true},
+		{coffee, fieldKana, "コーヒー", true},
+		{coffee, fieldRomaji, "kōhī", true},
+		{coffee, fieldRomaji, "kohi", false},
+	}
+	for _, tt := range tests {
+		if got := m.correct(tt.w, tt.f, tt.ans); got != tt.want {
+			t.Errorf("correct(%s, %s, %q) = %v, want %v", tt.w.Kana, tt.f, tt.ans, got, tt.want)
+		}
+	}
+}
+
+func TestMatrixQuestion(t *testing.T) {
+	m, err := parseMatrix("汉字&中文 => 假名&罗马音")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := WordItem{Kana: "がっこう", Kanji: "学校", Chines: []string{"学校", "校园"}}
+	if got, want := m.question(w), "汉字: 学校    中文: 学校/校园"; got != want {
+		t.Errorf("question = %q, want %q", got, want)
+	}
+	if got, want := m.expected(w), "がっこう / gakkou"; got != want {
+		t.Errorf("expected = %q, want %q", got, want)
+	}
+
+	// 只显示汉字时跳过没有汉字的单词
+	onlyKanji, _ := parseMatrix("汉字 => 假名")
+	if onlyKanji.usable(WordItem{Kana: "コーヒー"}) || !onlyKanji.usable(w) {
+		t.Error("usable should require a non-empty prompt field")
+	}
+}
@@ -0,0 +1,103 @@
+package vocabulary
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	"FiftySound/modules/history"
+	"FiftySound/modules/mistakes"
+	"FiftySound/modules/session"
+)
+
+// ==================================================
+// 错题本：模式1、模式2和模式4答错的单词记入错题本（连同单词的完整内容，
+// 词库变化后仍然可以练习）；"只练错题"时连续答对指定次数后移出
+// ==================================================
+
+// noteMistake 更新错题本，由 answer 在每道题第一次判题时调用；模式3为自评，不记错题
+func (p *WordPool) noteMistake(w WordItem, given string, correct bool) (cleared bool) {
+	if p.mode == modeThreeWordsName {
+		return false
+	}
+	if correct {
+		return p.practice && p.book.Hit(history.ModuleWord, p.mode, wordKey(w))
+	}
+
+	data, err := json.Marshal(w)
+	if err != nil {
+		fyne.LogError("保存错题失败", err)
+		return false
+	}
+	e := mistakes.Entry{
+		Module: history.ModuleWord,
+		Mode:   p.mode,
+		Item:   wordKey(w),
+		Group:  w.Unit,
+		Answer: given,
+		Data:   data,
+	}
+	meaning := strings.Join(w.Chines, "/")
+	switch {
+	case p.matrix != nil:
+		e.Question, e.Expected = p.matrix.question(w), p.matrix.expected(w)
+	case p.mode == modeOneWordsName:
+		e.Question, e.Expected = meaning, w.Kana+" / "+w.Kanji
+	default:
+		e.Question, e.Expected = fmt.Sprintf("%s (%s)", w.Kana, w.Kanji), meaning
+	}
+	p.book.Miss(e)
+	return false
+}
+
+// practiceTitle 只练错题时在窗口标题后注明
+func practiceTitle(mode string, practice bool) string {
+	if practice {
+		return mode + " - 只练错题"
+	}
+	return mode
+}
+
+// clearedText 只练错题时答对并移出错题本的提示
+func clearedText(myApp fyne.App, cleared bool) string {
+	if !cleared {
+		return ""
+	}
+	return fmt.Sprintf("已连续答对 %d 次，移出错题本", mistakes.ClearAfter(myApp))
+}
+
+// PracticeMistakes 用答错时的模式练习错题本中的单词，entries 应属于同一个模式
+func PracticeMistakes(myApp fyne.App, parent fyne.Window, entries []mistakes.Entry) {
+	var words []WordItem
+	mode := ""
+	for _, e := range entries {
+		var w WordItem
+		if err := json.Unmarshal(e.Data, &w); err != nil {
+			fyne.LogError("读取错题失败", err)
+			continue
+		}
+		w.Unit = e.Group
+		words = append(words, w)
+		mode = e.Mode
+	}
+	if len(words) == 0 {
+		dialog.ShowInformation("提示", "没有可以练习的单词错题", parent)
+		return
+	}
+	switch mode {
+	case modeOneWordsName:
+		showModeOneWords(myApp, parent, words, session.Load(myApp), true)
+	case modeTwoWordsName:
+		showModeTwoWords(myApp, parent, words, session.Load(myApp), true)
+	default:
+		m, err := parseMatrix(mode)
+		if err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		showMatrixWords(myApp, parent, words, m, session.Load(myApp), true)
+	}
+}
@@ -0,0 +1,72 @@
+package vocabulary
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/romaji"
+)
+
+// ==================================================
+// 单词的罗马音：练习时在假名后面显示罗马音（拼写方式可选），
+// 作答假名时也可以直接输入罗马音
+// ==================================================
+
+const (
+	romajiPrefKey = "vocabulary.romaji"
+	romajiHidden  = "不显示罗马音"
+)
+
+// romajiOptions 下拉框选项，顺序与 romaji.Systems 一致，最后是不显示
+func romajiOptions() []string {
+	var opts []string
+	for _, sys := range romaji.Systems {
+		opts = append(opts, "罗马音: "+sys.String())
+	}
+	return append(opts, romajiHidden)
+}
+
+// romajiSystem 当前选择的拼写方式，不显示罗马音时 ok 为 false
+func romajiSystem(myApp fyne.App) (sys romaji.System, ok bool) {
+	i := myApp.Preferences().IntWithFallback(romajiPrefKey, int(romaji.Hepburn))
+	if i < 0 || i >= len(romaji.Systems) {
+		return 0, false
+	}
+	return romaji.Systems[i], true
+}
+
+// newRomajiSelect 选择罗马音拼写方式的下拉框，选择保存在本地
+func newRomajiSelect(myApp fyne.App) *widget.Select {
+	sel := widget.NewSelect(romajiOptions(), nil)
+	if sys, ok := romajiSystem(myApp); ok {
+		sel.SetSelectedIndex(int(sys))
+	} else {
+		sel.SetSelected(romajiHidden)
+	}
+	sel.OnChanged = func(string) {
+		i := sel.SelectedIndex()
+		if i >= len(romaji.Systems) {
+			i = -1
+		}
+		myApp.Preferences().SetInt(romajiPrefKey, i)
+	}
+	return sel
+}
+
+// withRomaji 假名后面附上罗马音，如 "がっこう [gakkou]"
+func withRomaji(myApp fyne.App, kana string) string {
+	sys, ok := romajiSystem(myApp)
+	if !ok || kana == "" {
+		return kana
+	}
+	return fmt.Sprintf("%s [%s]", kana, romaji.ToRomaji(kana, sys))
+}
+
+// kanaAnswerCorrect 作答的假名与 kana 一致（不区分平假名和片假名，长音的写法也可以不同），
+// 或者输入的是 kana 的某种罗马音写法。输入框默认把罗马音转换成平假名，
+// 片假名的单词也要能直接用罗马音作答
+func kanaAnswerCorrect(answer, kana string) bool {
+	return answer == kana || romaji.Match(answer, kana)
+}
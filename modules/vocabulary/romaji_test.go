@@ -0,0 +1,32 @@
+package vocabulary
+
+import "testing"
+
+func TestKanaAnswerCorrect(t *testing.T) {
+	tests := []struct {
+		answer, kana string
+		want         bool
+	}{
+		{"がっこう", "がっこう", true},
+		{"gakkou", "がっこう", true},
+		{"がこう", "がっこう", false},
+		// 输入框默认转换成平假名，片假名的单词也要算对
+		{"こーひー", "コーヒー", true},
+		{"コーヒー", "コーヒー", true},
+		{"ko-hi-", "コーヒー", true},
+		{"kōhī", "コーヒー", true},
+		{"こうひい", "コーヒー", true},
+		{"こーら", "コーヒー", false},
+		// 平假名和片假名混写的单词
+		{"あるばいとする", "アルバイトする", true},
+		{"arubaitosuru", "アルバイトする", true},
+		{"アルバイトする", "アルバイトする", true},
+		{"あるばいと", "アルバイトする", false},
+		{"", "コーヒー", false},
+	}
+	for _, tt := range tests {
+		if got := kanaAnswerCorrect(tt.answer, tt.kana); got != tt.want {
+			t.Errorf("kanaAnswerCorrect(%q, %q) = %v, want %v", tt.answer, tt.kana, got, tt.want)
+		}
+	}
+}
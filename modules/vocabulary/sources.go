@@ -0,0 +1,296 @@
+package vocabulary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ==================================================
+// 词库来源：可以配置多个词库（远程 ZIP 地址或本地文件夹/ZIP），
+// 每个来源在单元选择树中是一个独立的顶层节点，配置保存在应用的 Preferences 中
+// ==================================================
+
+// Source 一个词库来源
+type Source struct {
+	Name     string `json:"name"`     // 在单元选择树中显示的名字
+	Location string `json:"location"` // http(s) 地址，或本地文件夹/ZIP 的路径
+	RootDir  string `json:"rootDir"`  // 词库所在的目录名，为空时使用整个来源
+}
+
+const (
+	sourcesPrefKey = "vocabulary.sources"
+	// sourceRoot 词库来源在目录树中的路径前缀
+	sourceRoot = "SOURCE"
+)
+
+// defaultSource 默认的词库：GitHub 上的新标日第二版单词
+var defaultSource = Source{
+	Name:     "标准日本语第二版",
+	Location: githubZipURL,
+	RootDir:  "vocabularyLib",
+}
+
+// remote 是否需要从网络下载
+func (s Source) remote() bool {
+	l := strings.ToLower(s.Location)
+	return strings.HasPrefix(l, "http://") || strings.HasPrefix(l, "https://")
+}
+
+// loadSources 读取已配置的词库来源，从未配置过时返回默认词库
+func loadSources(myApp fyne.App) []Source {
+	raw := myApp.Preferences().String(sourcesPrefKey)
+	if raw == "" {
+		return []Source{defaultSource}
+	}
+	var sources []Source
+	if err := json.Unmarshal([]byte(raw), &sources); err != nil {
+		fyne.LogError("读取词库来源失败", err)
+		return []Source{defaultSource}
+	}
+	return sources
+}
+
+func saveSources(myApp fyne.App, sources []Source) error {
+	// 保存空数组而不是空字符串，以区分“删除了全部来源”和“从未配置过”
+	if sources == nil {
+		sources = []Source{}
+	}
+	data, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+	myApp.Preferences().SetString(sourcesPrefKey, string(data))
+	return nil
+}
+
+// validateSource 检查来源是否填写完整，others 为其它来源，名字不能重复
+func validateSource(s Source, others []Source) error {
+	switch {
+	case s.Name == "":
+		return errors.New("请填写名称")
+	case strings.Contains(s.Name, "/"):
+		return errors.New("名称中不能包含 /")
+	case s.Location == "":
+		return errors.New("请填写地址或路径")
+	}
+	for _, o := range others {
+		if o.Name == s.Name {
+			return fmt.Errorf("已经有名为 %s 的词库", s.Name)
+		}
+	}
+	return nil
+}
+
+// loadSource 加载一个来源并挂到 t 下，返回离线等提示
+func loadSource(ctx context.Context, myApp fyne.App, t *vocabTree, src Source, progress progressFunc, status func(string)) (string, error) {
+	sub := newPackTree(sourceRoot+"/"+src.Name, src.Name)
+
+	notice := ""
+	if src.remote() {
+		data, n, err := fetchZip(ctx, myApp, src, progress, status)
+		if err != nil {
+			return "", err
+		}
+		notice = n
+		status("正在解析词库...")
+		if err := addZipEntries(sub, sub.root, data, true); err != nil {
+			return "", err
+		}
+	} else {
+		status("正在读取本地词库...")
+		if err := addLocalEntries(sub, sub.root, src.Location); err != nil {
+			return "", err
+		}
+	}
+
+	top := sub.root
+	if src.RootDir != "" {
+		top = findDirByName(sub.root, src.RootDir)
+		if top == nil {
+			return "", fmt.Errorf("未找到 %s 目录", src.RootDir)
+		}
+		top.Name = src.Name
+	}
+	t.merge(sub)
+	t.roots = append(t.roots, top)
+	return notice, nil
+}
+
+// addLocalEntries 读取本地的文件夹或 ZIP
+func addLocalEntries(t *vocabTree, root *DirEntry, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return addZipEntries(t, root, data, true)
+	}
+	dir, err := storage.ListerForURI(storage.NewFileURI(path))
+	if err != nil {
+		return err
+	}
+	return addFolderEntries(t, root, dir, "")
+}
+
+// showSourceSettings 词库来源设置窗口，保存后调用 onSaved 重新加载词库
+func showSourceSettings(myApp fyne.App, parent fyne.Window, onSaved func()) {
+	win := myApp.NewWindow("词库来源设置")
+
+	sources := loadSources(myApp)
+	selected := -1
+
+	list := widget.NewList(
+		func() int { return len(sources) },
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			s := sources[id]
+			text := s.Name + "\n" + s.Location
+			if s.RootDir != "" {
+				text += fmt.Sprintf("  (目录: %s)", s.RootDir)
+			}
+			obj.(*widget.Label).SetText(text)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	others := func(skip int) []Source {
+		var res []Source
+		for i, s := range sources {
+			if i != skip {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+
+	addBtn := widget.NewButton("添加", func() {
+		showSourceForm(Source{}, others(-1), win, func(s Source) {
+			sources = append(sources, s)
+			list.Refresh()
+		})
+	})
+	editBtn := widget.NewButton("编辑", func() {
+		if selected < 0 {
+			dialog.ShowInformation("提示", "请先选择一个词库", win)
+			return
+		}
+		i := selected
+		showSourceForm(sources[i], others(i), win, func(s Source) {
+			sources[i] = s
+			list.Refresh()
+		})
+	})
+	removeBtn := widget.NewButton("删除", func() {
+		if selected < 0 {
+			dialog.ShowInformation("提示", "请先选择一个词库", win)
+			return
+		}
+		sources = others(selected)
+		list.UnselectAll()
+		list.Refresh()
+	})
+	resetBtn := widget.NewButton("恢复默认", func() {
+		sources = []Source{defaultSource}
+		list.UnselectAll()
+		list.Refresh()
+	})
+
+	saveBtn := widget.NewButton("保存并重新加载", func() {
+		if err := saveSources(myApp, sources); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		win.Close()
+		onSaved()
+	})
+	cancelBtn := widget.NewButton("取消", func() {
+		win.Close()
+	})
+
+	win.SetContent(container.NewBorder(
+		widget.NewLabel("每个词库在\"选择需要练习的单元\"中显示为一个顶层目录"),
+		container.NewVBox(
+			container.NewHBox(addBtn, editBtn, removeBtn, resetBtn),
+			container.NewHBox(saveBtn, cancelBtn),
+		),
+		nil, nil,
+		list,
+	))
+	win.Resize(fyne.NewSize(560, 400))
+	win.Show()
+}
+
+// showSourceForm 添加或编辑一个来源
+func showSourceForm(src Source, others []Source, win fyne.Window, onDone func(Source)) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(src.Name)
+	locEntry := widget.NewEntry()
+	locEntry.SetText(src.Location)
+	locEntry.SetPlaceHolder("https://.../xxx.zip 或本地路径")
+	rootEntry := widget.NewEntry()
+	rootEntry.SetText(src.RootDir)
+	rootEntry.SetPlaceHolder("可选，如 vocabularyLib")
+
+	folderBtn := widget.NewButton("选择文件夹", func() {
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err == nil && dir != nil {
+				locEntry.SetText(dir.Path())
+			}
+		}, win)
+	})
+	zipBtn := widget.NewButton("选择ZIP", func() {
+		fd := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err == nil && reader != nil {
+				locEntry.SetText(reader.URI().Path())
+				reader.Close()
+			}
+		}, win)
+		fd.SetFilter(storage.NewExtensionFileFilter([]string{".zip"}))
+		fd.Show()
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("名称", nameEntry),
+		widget.NewFormItem("地址或路径", locEntry),
+		widget.NewFormItem("", container.NewHBox(folderBtn, zipBtn)),
+		widget.NewFormItem("词库目录名", rootEntry),
+	}
+	dlg := dialog.NewForm("词库来源", "确定", "取消", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		s := Source{
+			Name:     strings.TrimSpace(nameEntry.Text),
+			Location: strings.TrimSpace(locEntry.Text),
+			RootDir:  strings.Trim(strings.TrimSpace(rootEntry.Text), "/"),
+		}
+		if !s.remote() && s.Location != "" {
+			s.Location = filepath.Clean(s.Location)
+		}
+		if err := validateSource(s, others); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		onDone(s)
+	}, win)
+	dlg.Resize(fyne.NewSize(480, 280))
+	dlg.Show()
+}
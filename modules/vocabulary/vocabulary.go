@@ -7,16 +7,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
-	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
+
+	"FiftySound/modules/history"
+	"FiftySound/modules/mistakes"
+	"FiftySound/modules/romaji"
+	"FiftySound/modules/session"
+	"FiftySound/modules/srs"
 )
 
 // ==================================================
@@ -28,12 +33,21 @@ type WordItem struct {
 	Kana   string   `json:"假名"`
 	Kanji  string   `json:"日本汉字"`
 	Chines []string `json:"中文释义"`
+	Unit   string   `json:"-"` // 所在的单元文件，加载时填写
 }
 
 func sameWord(a, b WordItem) bool {
 	return a.Kana == b.Kana && a.Kanji == b.Kanji
 }
 
+// 练习模式
+const (
+	modeOneWordsName   = "模式1: 中文 => 假名&汉字"
+	modeTwoWordsName   = "模式2: 假名(汉字) => 中文"
+	modeThreeWordsName = "模式3: 背单词"
+	modeFourWordsName  = "模式4: 自定义练习"
+)
+
 const githubZipURL = "https://github.com/CloudGee/JapaneseVocabulary/archive/refs/heads/main.zip"
 
 // 保存每个节点的选中状态
@@ -51,59 +65,69 @@ type DirEntry struct {
 
 // 全局
 var (
-	rootDir      *DirEntry
-	vocabDir     *DirEntry
-	nodeIndex    map[string]*DirEntry
-	fileContents map[string][]byte
+	// 当前词库，由后台线程解析完成后通过 setVocabTree 交给界面
+	treeMu sync.RWMutex
+	tree   *vocabTree
 
 	// 选中的单词
 	selectedWords []WordItem
 )
 
+// vocabTree 一次解析得到的词库目录树
+type vocabTree struct {
+	root     *DirEntry
+	roots    []*DirEntry // 选择单元时显示的顶层节点：各个词库来源和导入的单元包
+	index    map[string]*DirEntry
+	contents map[string][]byte
+}
+
+func currentTree() *vocabTree {
+	treeMu.RLock()
+	defer treeMu.RUnlock()
+	return tree
+}
+
+func setVocabTree(t *vocabTree) {
+	treeMu.Lock()
+	defer treeMu.Unlock()
+	// 重新加载词库时保留已导入的单元包
+	if tree != nil {
+		t.adoptImports(tree)
+	}
+	tree = t
+}
+
 // ==================================================
 // 1. 模块主页面：ShowVocabularyMainPage
-//    先加载词库，然后进入“模块主页面”
+//    先在后台加载词库，然后进入“模块主页面”
 // ==================================================
 
-// ShowVocabularyMainPage 主界面改用下拉框选择模式
 // ShowVocabularyMainPage 主界面改用下拉框选择模式
 func ShowVocabularyMainPage(myApp fyne.App, parent fyne.Window) {
-	progress := dialog.NewProgressInfinite("提示", "正在从GitHub请求最新词库...", parent)
-	progress.Show()
-
-	var err error
-	maxRetries := 4
-	retryDelay := 2 * time.Second
-
-	for i := 0; i < maxRetries; i++ {
-		err = loadZipAndInit()
-		if err == nil {
-			fmt.Println("Operation succeeded")
-			break
-		}
-
-		if i < maxRetries-1 {
-			time.Sleep(retryDelay)
-		}
-	}
-
-	progress.Hide()
-
-	if err != nil {
-		dialog.ShowError(err, parent)
-		return
-	}
+	loadVocabularyAsync(myApp, parent, func(notice string) {
+		showVocabularyWindow(myApp, notice)
+	})
+}
 
+// showVocabularyWindow 词库加载完成后显示模块主页面，notice 为离线等提示
+func showVocabularyWindow(myApp fyne.App, notice string) {
 	mainWin := myApp.NewWindow("新标日语单词练习 - 模块主页面")
 
 	// 下拉框选择模式
 	modeSelect := widget.NewSelect([]string{
-		"模式1: 中文 => 假名&汉字",
-		"模式2: 假名(汉字) => 中文",
-		"模式3: 背单词",
+		modeOneWordsName,
+		modeTwoWordsName,
+		modeThreeWordsName,
+		modeFourWordsName,
 	}, nil)
 	modeSelect.PlaceHolder = "请点击下拉框，选择你想要的模式"
 
+	// 练习时假名后面显示的罗马音
+	romajiSelect := newRomajiSelect(myApp)
+
+	// 每次练习的题量
+	lengthSelect := session.NewLengthSelect(myApp)
+
 	// 开始按钮
 	startBtn := widget.NewButton("开始", func() {
 		if modeSelect.Selected == "" {
@@ -115,13 +139,16 @@ func ShowVocabularyMainPage(myApp fyne.App, parent fyne.Window) {
 			return
 		}
 
+		length := session.Load(myApp)
 		switch modeSelect.Selected {
-		case "模式1: 中文 => 假名&汉字":
-			showModeOneWords(myApp, mainWin, selectedWords)
-		case "模式2: 假名(汉字) => 中文":
-			showModeTwoWords(myApp, mainWin, selectedWords)
-		case "模式3: 背单词":
-			showModeThreeWords(myApp, mainWin, selectedWords)
+		case modeOneWordsName:
+			showModeOneWords(myApp, mainWin, selectedWords, length, false)
+		case modeTwoWordsName:
+			showModeTwoWords(myApp, mainWin, selectedWords, length, false)
+		case modeThreeWordsName:
+			showModeThreeWords(myApp, mainWin, selectedWords, length)
+		case modeFourWordsName:
+			showMatrixSettings(myApp, mainWin, selectedWords, length)
 		}
 	})
 
@@ -130,11 +157,51 @@ func ShowVocabularyMainPage(myApp fyne.App, parent fyne.Window) {
 		showSelectTree(myApp, mainWin)
 	})
 
+	// 导入本地单元包
+	importFolderBtn := widget.NewButton("导入本地文件夹", func() {
+		showImportFolderDialog(mainWin)
+	})
+	importZipBtn := widget.NewButton("导入本地ZIP", func() {
+		showImportZipDialog(mainWin)
+	})
+	importDeckBtn := widget.NewButton("导入Anki/CSV", func() {
+		showImportDeckDialog(mainWin)
+	})
+
+	// 导出选中的单词
+	exportBtn := widget.NewButton("导出选中单词", func() {
+		showExportDialog(myApp, mainWin)
+	})
+
+	// 离线时提示正在使用缓存
+	noticeLabel := widget.NewLabel("")
+	setNotice := func(notice string) {
+		noticeLabel.SetText(notice)
+		if notice == "" {
+			noticeLabel.Hide()
+		} else {
+			noticeLabel.Show()
+		}
+	}
+	setNotice(notice)
+
+	// 词库来源设置，保存后重新加载所有词库
+	sourcesBtn := widget.NewButton("词库来源设置", func() {
+		showSourceSettings(myApp, mainWin, func() {
+			loadVocabularyAsync(myApp, mainWin, setNotice)
+		})
+	})
+
 	mainWin.SetContent(container.NewVBox(
 		widget.NewLabel("新标日语单词练习 (模块主页面)"),
+		noticeLabel,
 		widget.NewLabel("请选择操作："),
 		selBtn,
+		container.NewHBox(importFolderBtn, importZipBtn, importDeckBtn),
+		container.NewHBox(sourcesBtn, exportBtn),
 		modeSelect,
+		romajiSelect,
+		lengthSelect,
 		startBtn, // 替换为开始按钮
 	))
 	mainWin.Resize(fyne.NewSize(400, 300))
@@ -142,16 +209,29 @@ func ShowVocabularyMainPage(myApp fyne.App, parent fyne.Window) {
 }
 
 func showSelectTree(myApp fyne.App, parent fyne.Window) {
-	selWin := myApp.NewWindow("选择需要练习的单元")
-	if vocabDir == nil {
-		dialog.ShowInformation("提示", "未找到vocabularyLib目录", parent)
+	t := currentTree()
+	if t == nil || len(t.roots) == 0 {
+		dialog.ShowInformation("提示", "没有可用的词库，请检查词库来源设置或导入本地单元包", parent)
 		return
 	}
+	// 导入单元包时会修改 t.roots 和 t.index，这里在锁内复制一份给树控件使用；
+	// 节点本身导入后不再修改，可以共用
+	treeMu.RLock()
+	roots := make([]string, len(t.roots))
+	for i, r := range t.roots {
+		roots[i] = r.FullPath
+	}
+	nodeIndex := make(map[string]*DirEntry, len(t.index))
+	for k, v := range t.index {
+		nodeIndex[k] = v
+	}
+	treeMu.RUnlock()
+	selWin := myApp.NewWindow("选择需要练习的单元")
 
 	myTree := widget.NewTree(
 		func(uid string) []string {
 			if uid == "" {
-				return []string{vocabDir.FullPath}
+				return roots
 			}
 			nd := nodeIndex[uid]
 			if nd == nil {
@@ -287,26 +367,71 @@ func updateParentChecked(n *DirEntry) {
 	updateParentChecked(parent)
 }
 
-// loadZipAndInit 下载 ZIP 到内存并解析
-func loadZipAndInit() error {
-	data, err := downloadZip(githubZipURL)
-	if err != nil {
-		return err
-	}
-	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
-	if err != nil {
-		return fmt.Errorf("ZIP解压失败: %w", err)
-	}
-
+func newVocabTree() *vocabTree {
 	// 构造 rootDir
-	rootDir = &DirEntry{
+	rootDir := &DirEntry{
 		Name:     "ROOT",
 		FullPath: "ROOT",
 		IsDir:    true,
 	}
-	nodeIndex = make(map[string]*DirEntry)
-	nodeIndex["ROOT"] = rootDir
-	fileContents = make(map[string][]byte)
+	return &vocabTree{
+		root:     rootDir,
+		index:    map[string]*DirEntry{"ROOT": rootDir},
+		contents: make(map[string][]byte),
+	}
+}
+
+// newPackTree 新建以 fullPath 为根的临时树：先在临时树中解析，全部成功后再用 merge 合并，
+// 避免失败时留下残缺的节点
+func newPackTree(fullPath, name string) *vocabTree {
+	root := &DirEntry{
+		Name:     name,
+		FullPath: fullPath,
+		IsDir:    true,
+	}
+	return &vocabTree{
+		root:     root,
+		index:    map[string]*DirEntry{fullPath: root},
+		contents: make(map[string][]byte),
+	}
+}
+
+// merge 把 sub 的节点和文件内容合并到 t 中，不修改 roots
+func (t *vocabTree) merge(sub *vocabTree) {
+	for k, v := range sub.index {
+		t.index[k] = v
+	}
+	for k, v := range sub.contents {
+		t.contents[k] = v
+	}
+}
+
+// adoptImports 把 old 中导入的单元包搬到 t 中
+func (t *vocabTree) adoptImports(old *vocabTree) {
+	prefix := importedRoot + "/"
+	for _, r := range old.roots {
+		if strings.HasPrefix(r.FullPath, prefix) {
+			t.roots = append(t.roots, r)
+		}
+	}
+	for k, v := range old.index {
+		if strings.HasPrefix(k, prefix) {
+			t.index[k] = v
+		}
+	}
+	for k, v := range old.contents {
+		if strings.HasPrefix(k, prefix) {
+			t.contents[k] = v
+		}
+	}
+}
+
+// addZipEntries 把 ZIP 中的文件挂到 root 下；onlyJSON 为 true 时忽略非 JSON 文件
+func addZipEntries(t *vocabTree, root *DirEntry, data []byte, onlyJSON bool) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("ZIP解压失败: %w", err)
+	}
 
 	// 目录优先
 	files := make([]*zip.File, len(zr.File))
@@ -318,33 +443,26 @@ func loadZipAndInit() error {
 		return files[i].Name < files[j].Name
 	})
 	for _, f := range files {
-		createDirEntry(rootDir, f)
-	}
-
-	// 找到 vocabularyLib
-	vocabDir = findDirByName(rootDir, "vocabularyLib")
-	if vocabDir != nil {
-		vocabDir.Name = "标准日本语第二版"
+		zf := f
+		isDir := zf.FileInfo().IsDir()
+		if onlyJSON && (isDir || !strings.HasSuffix(strings.ToLower(zf.Name), ".json")) {
+			continue
+		}
+		createDirEntry(t, root, zf.Name, isDir, func() ([]byte, error) {
+			rc, err := zf.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		})
 	}
-
 	return nil
 }
 
-func downloadZip(url string) ([]byte, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("HTTP状态码=%d", resp.StatusCode)
-	}
-	return io.ReadAll(resp.Body)
-}
-
-func createDirEntry(root *DirEntry, zf *zip.File) {
-	parts := strings.Split(zf.Name, "/")
+// createDirEntry 按 "/" 分隔的 name 在 root 下逐级创建节点，文件内容由 read 读取
+func createDirEntry(t *vocabTree, root *DirEntry, name string, isDir bool, read func() ([]byte, error)) {
+	parts := strings.Split(name, "/")
 	curr := root
 	for i, p := range parts {
 		if p == "" {
@@ -362,16 +480,16 @@ func createDirEntry(root *DirEntry, zf *zip.File) {
 		}
 
 		if !found {
-			isDir := i < len(parts)-1 || zf.FileInfo().IsDir()
+			childIsDir := i < len(parts)-1 || isDir
 			newFullPath := curr.FullPath + "/" + p
 			child = &DirEntry{
 				Name:     p,
 				FullPath: newFullPath,
-				IsDir:    isDir,
+				IsDir:    childIsDir,
 				Parent:   curr,
 			}
 			curr.Children = append(curr.Children, child)
-			nodeIndex[newFullPath] = child
+			t.index[newFullPath] = child
 
 			// 目录优先再排序
 			sort.Slice(curr.Children, func(i, j int) bool {
@@ -386,13 +504,11 @@ func createDirEntry(root *DirEntry, zf *zip.File) {
 		curr = child
 
 		// 如果是文件 => 读内容
-		if i == len(parts)-1 && !zf.FileInfo().IsDir() {
-			rc, err := zf.Open()
+		if i == len(parts)-1 && !isDir {
+			bs, err := read()
 			if err == nil {
-				bs, _ := io.ReadAll(rc)
-				rc.Close()
 				curr.Content = bs
-				fileContents[curr.FullPath] = bs
+				t.contents[curr.FullPath] = bs
 			}
 		}
 	}
@@ -474,7 +590,13 @@ func collectJSON(n *DirEntry, files *[]string) {
 }
 
 func loadJSON(pathStr string) ([]WordItem, error) {
-	data, ok := fileContents[pathStr]
+	t := currentTree()
+	if t == nil {
+		return nil, errors.New("no content")
+	}
+	treeMu.RLock()
+	data, ok := t.contents[pathStr]
+	treeMu.RUnlock()
 	if !ok {
 		return nil, errors.New("no content")
 	}
@@ -482,66 +604,176 @@ func loadJSON(pathStr string) ([]WordItem, error) {
 	if err := json.Unmarshal(data, &arr); err != nil {
 		return nil, err
 	}
+	unit := unitName(pathStr)
+	for i := range arr {
+		arr[i].Unit = unit
+	}
 	return arr, nil
 }
 
+// unitName 把文件路径转换为显示用的单元名，如 "标准日本语第二版/初级上/第1课"
+func unitName(pathStr string) string {
+	name := strings.TrimSuffix(pathStr, ".json")
+	t := currentTree()
+	if t == nil {
+		return name
+	}
+	treeMu.RLock()
+	defer treeMu.RUnlock()
+	for _, r := range t.roots {
+		if strings.HasPrefix(name, r.FullPath+"/") {
+			return r.Name + strings.TrimPrefix(name, r.FullPath)
+		}
+	}
+	return name
+}
+
 // ==================================================
-// 3. 三种模式：showModeOneWords, showModeTwoWords, ...
+// 3. 练习模式：showModeOneWords, showModeTwoWords, ...
 // ==================================================
 
+// WordPool 按间隔重复调度出题：到期的单词优先，其次是没练过的
 type WordPool struct {
-	items []WordItem
-	index int
-	last  *WordItem
+	items    map[string]WordItem
+	keys     []string
+	sched    *srs.Scheduler
+	log      *history.Log
+	book     *mistakes.Book
+	mode     string
+	sess     *session.Session
+	practice bool         // 只练错题
+	judged   bool         // 当前题目是否已经判过，同一题多次判题只按第一次记录和安排复习
+	matrix   *fieldMatrix // 模式4的题目组合
+	last     string
+	shownAt  time.Time
 }
 
-func newWordPool(words []WordItem) *WordPool {
+func newWordPool(myApp fyne.App, mode string, words []WordItem, length session.Length) *WordPool {
 	p := &WordPool{
-		items: make([]WordItem, len(words)),
+		items: make(map[string]WordItem),
+		sched: wordScheduler(myApp),
+		log:   history.Open(myApp),
+		book:  mistakes.Open(myApp),
+		mode:  mode,
 	}
-	copy(p.items, words)
-	rand.Seed(time.Now().UnixNano())
-	p.shuffle()
+	for _, w := range words {
+		k := wordKey(w)
+		if _, ok := p.items[k]; ok {
+			continue
+		}
+		p.items[k] = w
+		p.keys = append(p.keys, k)
+	}
+	p.sess = session.New(length, len(p.keys))
 	return p
 }
 
-func (p *WordPool) shuffle() {
-	rand.Shuffle(len(p.items), func(i, j int) {
-		p.items[i], p.items[j] = p.items[j], p.items[i]
+func (p *WordPool) nextWord() WordItem {
+	// 避免连续相同
+	k := p.sched.Next(p.sess.Pending(p.keys), p.last)
+	p.last = k
+	p.judged = false
+	p.shownAt = time.Now()
+	return p.items[k]
+}
+
+// answer 记录判题结果：写入答题记录和本次练习，并更新复习进度（答错的单词会很快再次出现），
+// 同时更新错题本，cleared 表示这道题连续答对，已经移出错题本；同一题只记录第一次判题
+func (p *WordPool) answer(w WordItem, given string, correct bool, m session.Mistake) (cleared bool) {
+	if p.judged {
+		return false
+	}
+	p.judged = true
+	p.sess.Add(wordKey(w), correct, m)
+	p.log.Add(history.Record{
+		Module:  history.ModuleWord,
+		Mode:    p.mode,
+		Item:    wordKey(w),
+		Group:   w.Unit,
+		Answer:  given,
+		Correct: correct,
+		Latency: time.Since(p.shownAt),
 	})
-	p.index = 0
+	if err := p.sched.ReviewResult(wordKey(w), correct); err != nil {
+		fyne.LogError("保存复习进度失败", err)
+	}
+	return p.noteMistake(w, given, correct)
 }
 
-func (p *WordPool) nextWord() WordItem {
-	if p.index >= len(p.items) {
-		p.shuffle()
+// accuracyText 本次练习（从 since 开始）的正确率
+func (p *WordPool) accuracyText(since time.Time) string {
+	acc := p.log.Accuracy(history.Filter{Module: history.ModuleWord, Mode: p.mode, Since: since})
+	return fmt.Sprintf("当前正确率: %.2f%% (%d/%d)", acc.Rate(), acc.Correct, acc.Total)
+}
+
+// missedWords 重练错题时使用：keys 对应的单词
+func (p *WordPool) missedWords(keys []string) []WordItem {
+	var res []WordItem
+	for _, k := range keys {
+		res = append(res, p.items[k])
 	}
-	item := p.items[p.index]
-	p.index++
+	return res
+}
 
-	// 避免连续相同
-	if p.last != nil && sameWord(item, *p.last) && len(p.items) > 1 {
-		p.shuffle()
-		item = p.items[0]
-		p.index = 1
+// wordKey 是单词在复习调度中的标识
+func wordKey(w WordItem) string {
+	return w.Kana + "|" + w.Kanji
+}
+
+// 单词模块共用的复习调度器，首次使用时从应用存储中读取
+var wordSRS *srs.Scheduler
+
+func wordScheduler(myApp fyne.App) *srs.Scheduler {
+	if wordSRS == nil {
+		s, err := srs.Load(myApp, "words")
+		if err != nil {
+			fyne.LogError("读取复习进度失败", err)
+		}
+		wordSRS = s
 	}
-	p.last = &item
-	return item
+	return wordSRS
 }
 
 // 模式1: "中文" => 假名&汉字
-func showModeOneWords(myApp fyne.App, parent fyne.Window, words []WordItem) {
-	win := myApp.NewWindow("模式1: 中文 => 假名&汉字")
+// practice 为 true 时是错题本中的"只练错题"
+func showModeOneWords(myApp fyne.App, parent fyne.Window, words []WordItem, length session.Length, practice bool) {
+	win := myApp.NewWindow(practiceTitle(modeOneWordsName, practice))
 
-	pool := newWordPool(words)
+	pool := newWordPool(myApp, modeOneWordsName, words, length)
+	pool.practice = practice
+	progress := widget.NewLabel("")
 	question := widget.NewLabel("")
-	kanaEntry := widget.NewEntry()
+	kanaEntry := romaji.NewKanaEntry(romaji.Hiragana)
+	kanaEntry.SetPlaceHolder("可以直接输入罗马音")
+	// 输入罗马音时转换成平假名还是片假名，使用系统输入法时可以关闭
+	scriptRadio := widget.NewRadioGroup([]string{"平假名", "片假名", "不转换"}, func(s string) {
+		switch s {
+		case "片假名":
+			kanaEntry.SetScript(romaji.Katakana)
+		case "不转换":
+			kanaEntry.SetScript(romaji.Off)
+		default:
+			kanaEntry.SetScript(romaji.Hiragana)
+		}
+	})
+	scriptRadio.Horizontal = true
+	scriptRadio.SetSelected("平假名")
 	kanjiEntry := widget.NewEntry()
 	feedback := widget.NewLabel("")
+	openedAt := time.Now()
+	statsLabel := widget.NewLabel(pool.accuracyText(openedAt))
 
 	var current WordItem
 
 	var refresh = func() {
+		if pool.sess.Finished() {
+			session.ShowSummary(win, pool.sess, func(missed []string) {
+				win.Close()
+				showModeOneWords(myApp, parent, pool.missedWords(missed), session.EachOnce, practice)
+			})
+			return
+		}
+		progress.SetText(pool.sess.Progress())
 		kanaEntry.SetText("")
 		kanjiEntry.SetText("")
 		feedback.SetText("")
@@ -550,13 +782,20 @@ func showModeOneWords(myApp fyne.App, parent fyne.Window, words []WordItem) {
 	}
 
 	judgeBtn := widget.NewButton("判题", func() {
-		k := strings.TrimSpace(kanaEntry.Text)
+		k := kanaEntry.Kana()
 		j := strings.TrimSpace(kanjiEntry.Text)
-		if k == current.Kana && j == current.Kanji {
-			feedback.SetText("正确！")
+		correct := kanaAnswerCorrect(k, current.Kana) && j == current.Kanji
+		cleared := pool.answer(current, k+" / "+j, correct, session.Mistake{
+			Question: question.Text,
+			Answer:   k + " / " + j,
+			Expected: current.Kana + " / " + current.Kanji,
+		})
+		if correct {
+			feedback.SetText("正确！" + clearedText(myApp, cleared))
 		} else {
-			feedback.SetText(fmt.Sprintf("错误，正确答案: %s / %s", current.Kana, current.Kanji))
+			feedback.SetText(fmt.Sprintf("错误，正确答案: %s / %s", withRomaji(myApp, current.Kana), current.Kanji))
 		}
+		statsLabel.SetText(pool.accuracyText(openedAt))
 	})
 
 	nextBtn := widget.NewButton("下一题", func() {
@@ -568,11 +807,13 @@ func showModeOneWords(myApp fyne.App, parent fyne.Window, words []WordItem) {
 	})
 
 	win.SetContent(container.NewVBox(
+		progress,
 		question,
-		widget.NewLabel("假名："), kanaEntry,
+		container.NewHBox(widget.NewLabel("假名："), scriptRadio), kanaEntry,
 		widget.NewLabel("汉字："), kanjiEntry,
 		container.NewHBox(judgeBtn, nextBtn),
 		feedback,
+		statsLabel,
 		closeBtn,
 	))
 	win.Resize(fyne.NewSize(400, 300))
@@ -581,21 +822,34 @@ func showModeOneWords(myApp fyne.App, parent fyne.Window, words []WordItem) {
 }
 
 // 模式2: "假名(汉字)" => 中文
-func showModeTwoWords(myApp fyne.App, parent fyne.Window, words []WordItem) {
-	win := myApp.NewWindow("模式2: 假名(汉字) => 中文")
+// practice 为 true 时是错题本中的"只练错题"
+func showModeTwoWords(myApp fyne.App, parent fyne.Window, words []WordItem, length session.Length, practice bool) {
+	win := myApp.NewWindow(practiceTitle(modeTwoWordsName, practice))
 
-	pool := newWordPool(words)
+	pool := newWordPool(myApp, modeTwoWordsName, words, length)
+	pool.practice = practice
+	progress := widget.NewLabel("")
 	question := widget.NewLabel("")
 	answerEntry := widget.NewEntry()
 	feedback := widget.NewLabel("")
+	openedAt := time.Now()
+	statsLabel := widget.NewLabel(pool.accuracyText(openedAt))
 
 	var current WordItem
 
 	var refresh = func() {
+		if pool.sess.Finished() {
+			session.ShowSummary(win, pool.sess, func(missed []string) {
+				win.Close()
+				showModeTwoWords(myApp, parent, pool.missedWords(missed), session.EachOnce, practice)
+			})
+			return
+		}
+		progress.SetText(pool.sess.Progress())
 		answerEntry.SetText("")
 		feedback.SetText("")
 		current = pool.nextWord()
-		question.SetText(fmt.Sprintf("请填写中文: %s (%s)", current.Kana, current.Kanji))
+		question.SetText(fmt.Sprintf("请填写中文: %s (%s)", withRomaji(myApp, current.Kana), current.Kanji))
 	}
 
 	judgeBtn := widget.NewButton("判题", func() {
@@ -607,11 +861,17 @@ func showModeTwoWords(myApp fyne.App, parent fyne.Window, words []WordItem) {
 				break
 			}
 		}
+		cleared := pool.answer(current, ans, correct, session.Mistake{
+			Question: question.Text,
+			Answer:   ans,
+			Expected: strings.Join(current.Chines, "/"),
+		})
 		if correct {
-			feedback.SetText("正确！")
+			feedback.SetText("正确！" + clearedText(myApp, cleared))
 		} else {
 			feedback.SetText("错误！正确答案: " + strings.Join(current.Chines, "/"))
 		}
+		statsLabel.SetText(pool.accuracyText(openedAt))
 	})
 
 	nextBtn := widget.NewButton("下一题", func() {
@@ -623,43 +883,15 @@ func showModeTwoWords(myApp fyne.App, parent fyne.Window, words []WordItem) {
 	})
 
 	win.SetContent(container.NewVBox(
+		progress,
 		question,
 		answerEntry,
 		container.NewHBox(judgeBtn, nextBtn),
 		feedback,
+		statsLabel,
 		closeBtn,
 	))
 	win.Resize(fyne.NewSize(400, 300))
 	refresh()
 	win.Show()
 }
-
-// 模式3: 背单词 (显示中文+假名+汉字)
-func showModeThreeWords(myApp fyne.App, parent fyne.Window, words []WordItem) {
-	win := myApp.NewWindow("模式3: 背单词")
-
-	pool := newWordPool(words)
-	wordLabel := widget.NewLabel("")
-
-	var showOne = func() {
-		w := pool.nextWord()
-		wordLabel.SetText(fmt.Sprintf("[中文] %s\n[假名] %s\n[汉字] %s",
-			strings.Join(w.Chines, "/"), w.Kana, w.Kanji))
-	}
-
-	nextBtn := widget.NewButton("下一词", func() {
-		showOne()
-	})
-	closeBtn := widget.NewButton("关闭", func() {
-		win.Close()
-	})
-
-	win.SetContent(container.NewVBox(
-		wordLabel,
-		nextBtn,
-		closeBtn,
-	))
-	win.Resize(fyne.NewSize(400, 300))
-	showOne()
-	win.Show()
-}